@@ -3,6 +3,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/fs"
@@ -10,11 +11,14 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/klingtnet/static-site-generator/generator"
 	"github.com/klingtnet/static-site-generator/generator/renderer"
 	"github.com/klingtnet/static-site-generator/internal/fswatcher"
+	"github.com/klingtnet/static-site-generator/internal/livereload"
 	"github.com/klingtnet/static-site-generator/slug"
 	"github.com/urfave/cli/v2"
 	"github.com/yuin/goldmark"
@@ -26,6 +30,7 @@ import (
 const (
 	InternalError = iota + 1
 	BadArgument
+	BrokenLinks
 )
 
 func flagOverride(config *generator.Config, c *cli.Context) {
@@ -38,6 +43,21 @@ func flagOverride(config *generator.Config, c *cli.Context) {
 	if c.String("output") != "" {
 		config.OutputDir = c.String("output")
 	}
+	if c.Bool("incremental") {
+		config.Incremental = true
+	}
+	if c.Bool("no-prune") || c.Bool("prune-dry-run") {
+		config.NoPrune = true
+	}
+	if c.Bool("drafts") {
+		config.Drafts = true
+	}
+	if c.Bool("future") {
+		config.Future = true
+	}
+	if c.Bool("check-links") {
+		config.CheckLinks = true
+	}
 }
 
 type resources struct {
@@ -60,25 +80,32 @@ func initResources(config *generator.Config) (r *resources, err error) {
 	return
 }
 
-func setup(c *cli.Context) (
-	gen *generator.Generator,
-	config *generator.Config,
-	resources *resources,
-	err error,
-) {
-	config, err = generator.ParseConfigFile(c.String("config"))
+func loadConfig(c *cli.Context) (*generator.Config, error) {
+	config, err := generator.ParseConfigFile(c.String("config"))
 	if err != nil {
-		err = cli.Exit(
+		return nil, cli.Exit(
 			fmt.Sprintf("parsing config %q failed: %s", c.String("config"), err.Error()),
 			BadArgument,
 		)
-		return
 	}
 	flagOverride(config, c)
 
 	err = config.Validate()
 	if err != nil {
-		err = cli.Exit(fmt.Sprintf("bad config: %s", err.Error()), BadArgument)
+		return nil, cli.Exit(fmt.Sprintf("bad config: %s", err.Error()), BadArgument)
+	}
+
+	return config, nil
+}
+
+func setup(c *cli.Context) (
+	gen *generator.Generator,
+	config *generator.Config,
+	resources *resources,
+	err error,
+) {
+	config, err = loadConfig(c)
+	if err != nil {
 		return
 	}
 
@@ -89,13 +116,22 @@ func setup(c *cli.Context) (
 	}
 
 	slugifier := slug.NewSlugifier('-')
-	templates := renderer.NewTemplates(
+	templates, err := renderer.NewTemplates(
 		config.Author,
 		config.BaseURL,
 		slugifier,
 		resources.templateFS,
+		generator.DefaultTemplateFS(),
 	)
-	storage := generator.NewFileStorage(config.OutputDir)
+	if err != nil {
+		err = cli.Exit(fmt.Sprintf("bad templates: %s", err.Error()), BadArgument)
+		return
+	}
+	var storage generator.Storage = generator.NewFileStorage(config.OutputDir)
+	if c.Bool("prune-dry-run") {
+		// Only list stale files, without writing the build.
+		storage = generator.NewDryRunStorage(storage)
+	}
 
 	markdownOptions := []goldmark.Option{
 		goldmark.WithExtensions(extension.GFM, emoji.Emoji, extension.Footnote),
@@ -106,7 +142,20 @@ func setup(c *cli.Context) (
 			goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
 		)
 	}
-	renderer := renderer.NewMarkdown(goldmark.New(markdownOptions...), templates)
+	var stylesheets []string
+	if config.Highlight != nil {
+		markdownOptions = append(
+			markdownOptions,
+			goldmark.WithExtensions(renderer.Highlighting(config.Highlight.StyleName(), config.Highlight.LineNumbers)),
+		)
+		stylesheets = append(stylesheets, renderer.HighlightStylesheet)
+	}
+	// The table of contents is always installed, since the front-matter of pages can enable it.
+	markdownOptions = append(
+		markdownOptions,
+		goldmark.WithExtensions(renderer.TableOfContents(config.TOC.Options())),
+	)
+	renderer := renderer.NewMarkdown(goldmark.New(markdownOptions...), templates, stylesheets...)
 
 	gen = generator.New(
 		config,
@@ -130,6 +179,35 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return cli.Exit(fmt.Sprintf("generator failed: %s", err.Error()), InternalError)
 	}
+	logWarnings(generator.Warnings())
+
+	if c.Bool("prune-dry-run") {
+		stale, err := generator.Stale(c.Context)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("listing stale files failed: %s", err.Error()), InternalError)
+		}
+		for _, name := range stale {
+			fmt.Println("would remove", name)
+		}
+	}
+
+	return nil
+}
+
+func check(c *cli.Context) error {
+	config, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	report, err := generator.CheckLinks(c.Context, generator.NewFileStorage(config.OutputDir), config.BaseURL)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("checking links failed: %s", err.Error()), InternalError)
+	}
+	if len(report) > 0 {
+		fmt.Print(report)
+		return cli.Exit(fmt.Sprintf("found %d broken links", len(report)), BrokenLinks)
+	}
 
 	return nil
 }
@@ -154,15 +232,59 @@ func fileHandler(httpDir http.Dir, notFoundPage []byte) http.HandlerFunc {
 	})
 }
 
-func runServer(host string, port int, outputDir string) error {
+// redirectTable contains the redirects of the last build, keyed by their URL path.
+type redirectTable struct {
+	mu        sync.RWMutex
+	redirects map[string]string
+}
+
+func (rt *redirectTable) set(redirects []generator.Redirect) {
+	table := make(map[string]string, len(redirects))
+	for _, redirect := range redirects {
+		for _, from := range redirect.Paths() {
+			table[from] = redirect.To
+		}
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.redirects = table
+}
+
+func (rt *redirectTable) lookup(path string) (string, bool) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	to, ok := rt.redirects[path]
+
+	return to, ok
+}
+
+// redirectHandler responds with a permanent redirect if the requested path is an alias of a page.
+func redirectHandler(redirects *redirectTable, next http.Handler) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		to, ok := redirects.lookup(r.URL.Path)
+		if ok {
+			http.Redirect(w, r, to, http.StatusMovedPermanently)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func runServer(host string, port int, outputDir string, broker *livereload.Broker, redirects *redirectTable) error {
 	notFoundPage, err := os.ReadFile(filepath.Join(outputDir, "404.html"))
 	if err != nil {
 		notFoundPage = []byte(http.StatusText(http.StatusNotFound))
 	}
 
+	mux := http.NewServeMux()
+	mux.Handle(livereload.EventsPath, broker)
+	mux.Handle("/", redirectHandler(redirects, livereload.Inject(fileHandler(http.Dir(outputDir), notFoundPage))))
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", host, port),
-		Handler: fileHandler(http.Dir(outputDir), notFoundPage),
+		Handler: mux,
 	}
 	log.Printf("listening on http://%s", server.Addr)
 
@@ -175,16 +297,33 @@ func liveReload(c *cli.Context) error {
 		return err
 	}
 
-	checkInterval := c.Duration("check-interval")
-	watchers := []<-chan fswatcher.Result{
-		fswatcher.New(resources.sourceFS, time.NewTicker(checkInterval)).Watch(c.Context),
-		fswatcher.New(resources.staticFS, time.NewTicker(checkInterval)).Watch(c.Context),
-		fswatcher.New(resources.templateFS, time.NewTicker(checkInterval)).Watch(c.Context),
+	checkInterval, debounce := c.Duration("check-interval"), c.Duration("debounce")
+	watchers := []watchedDir{
+		{
+			dir:     config.ContentDir,
+			watcher: fswatcher.NewWatcher(resources.sourceFS, config.ContentDir, checkInterval, debounce),
+		},
+	}
+	if resources.staticFS != nil {
+		watchers = append(watchers, watchedDir{
+			dir:     config.StaticDir,
+			watcher: fswatcher.NewWatcher(resources.staticFS, config.StaticDir, checkInterval, debounce),
+		})
+	}
+	if config.TemplatesDir != "" {
+		// Default templates are embedded and will never change.
+		watchers = append(watchers, watchedDir{
+			dir:     config.TemplatesDir,
+			watcher: fswatcher.NewWatcher(resources.templateFS, config.TemplatesDir, checkInterval, debounce),
+		})
 	}
+	results := watchAll(c.Context, watchers)
 
+	broker := livereload.NewBroker()
+	redirects := &redirectTable{}
 	go func() {
 		for {
-			err = runServer(c.String("host"), c.Int("port"), config.OutputDir)
+			err := runServer(c.String("host"), c.Int("port"), config.OutputDir, broker, redirects)
 			if err != nil {
 				log.Printf("server crashed: %s", err.Error())
 				panic("exiting")
@@ -192,27 +331,104 @@ func liveReload(c *cli.Context) error {
 		}
 	}()
 
-	for {
-		hasChanged := false
+	for result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+		if !result.HasChanged {
+			continue
+		}
 
-		for _, watcher := range watchers {
-			result := <-watcher
-			if result.Err != nil {
-				return err
+		// The changed paths are only logged, every change triggers a full rebuild
+		// that skips unchanged files by the build cache, see the incremental flag.
+		// Changes that were reported by multiple watchers at once are coalesced into a single rebuild.
+		logChanges(result)
+		for pending := true; pending; {
+			select {
+			case next := <-results:
+				if next.Err != nil {
+					return next.Err
+				}
+				logChanges(next)
+			default:
+				pending = false
 			}
+		}
 
-			hasChanged = hasChanged || result.HasChanged
+		log.Println("rebuilding...")
+		err = generator.Run(c.Context)
+		if err != nil {
+			log.Printf("generator failed: %s", err.Error())
+			broker.Error(err)
+
+			continue
 		}
+		logWarnings(generator.Warnings())
+		redirects.set(generator.Redirects())
+		broker.Reload()
+	}
 
-		if hasChanged {
-			log.Println("something has changed, rebuilding...")
+	return nil
+}
 
-			err = generator.Run(c.Context)
-			if err != nil {
-				log.Printf("generator failed: %s", err.Error())
+func logWarnings(warnings []string) {
+	for _, warning := range warnings {
+		log.Println("warning:", warning)
+	}
+}
+
+// maxLoggedChanges limits the number of logged paths per kind of change,
+// e.g. the initial result of a watcher contains every watched file.
+const maxLoggedChanges = 5
+
+func logChanges(result fswatcher.Result) {
+	logPaths("added", result.Added)
+	logPaths("modified", result.Modified)
+	logPaths("removed", result.Removed)
+}
+
+func logPaths(kind string, paths []string) {
+	switch {
+	case len(paths) == 0:
+		return
+	case len(paths) > maxLoggedChanges:
+		log.Printf("%s %s and %d more", kind, strings.Join(paths[:maxLoggedChanges], ", "), len(paths)-maxLoggedChanges)
+	default:
+		log.Printf("%s %s", kind, strings.Join(paths, ", "))
+	}
+}
+
+// watchedDir is a directory and its watcher.
+type watchedDir struct {
+	dir     string
+	watcher fswatcher.Watcher
+}
+
+// watchAll merges the results of all watchers into a single channel.
+// Changed paths are prefixed with the watched directory.
+func watchAll(ctx context.Context, watchers []watchedDir) <-chan fswatcher.Result {
+	results := make(chan fswatcher.Result)
+	for _, watched := range watchers {
+		go func(dir string, watcherCh <-chan fswatcher.Result) {
+			for result := range watcherCh {
+				result.Added = prefixPaths(dir, result.Added)
+				result.Modified = prefixPaths(dir, result.Modified)
+				result.Removed = prefixPaths(dir, result.Removed)
+				results <- result
 			}
-		}
+		}(watched.dir, watched.watcher.Watch(ctx))
 	}
+
+	return results
+}
+
+func prefixPaths(dir string, paths []string) []string {
+	prefixed := make([]string, 0, len(paths))
+	for _, p := range paths {
+		prefixed = append(prefixed, filepath.Join(dir, filepath.FromSlash(p)))
+	}
+
+	return prefixed
 }
 
 func main() {
@@ -237,17 +453,51 @@ func main() {
 				Usage:    "config file to use",
 				Required: true,
 			},
+			&cli.BoolFlag{
+				Name:  "incremental",
+				Usage: "only generate files whose inputs changed since the last build",
+			},
+			&cli.BoolFlag{
+				Name:  "drafts",
+				Usage: "include pages that are marked as draft",
+			},
+			&cli.BoolFlag{
+				Name:  "future",
+				Usage: "include pages whose publish date is in the future",
+			},
+			&cli.BoolFlag{
+				Name:  "check-links",
+				Usage: "fail the build if internal links of the generated pages do not resolve",
+			},
+			&cli.BoolFlag{
+				Name:  "no-prune",
+				Usage: "keep files in the output folder that were not generated by the current build",
+			},
+			&cli.BoolFlag{
+				Name:  "prune-dry-run",
+				Usage: "list files in the output folder that would be pruned, without writing or removing any files",
+			},
 		},
 		Commands: []*cli.Command{
+			{
+				Name:   "check",
+				Usage:  "check that all internal links and anchors of the generated website resolve",
+				Action: check,
+			},
 			{
 				Name:  "livereload",
-				Usage: "start a webserver and rebuild website on every change",
+				Usage: "start a webserver, rebuild website on every change and reload the browser",
 				Flags: []cli.Flag{
 					&cli.DurationFlag{
 						Name:  "check-interval",
-						Usage: "how long to wait between checking for changed files",
+						Usage: "how long to wait between checking for changed files, if they can not be watched for events",
 						Value: 1 * time.Second,
 					},
+					&cli.DurationFlag{
+						Name:  "debounce",
+						Usage: "how long to wait for further file system events before rebuilding",
+						Value: 100 * time.Millisecond,
+					},
 					&cli.StringFlag{
 						Name:  "host",
 						Usage: "hostname the server should listen to",
@@ -258,6 +508,21 @@ func main() {
 						Usage: "port the server should listen to",
 						Value: 10000,
 					},
+					&cli.BoolFlag{
+						Name:  "incremental",
+						Usage: "only generate files whose inputs changed since the last build",
+						Value: true,
+					},
+					&cli.BoolFlag{
+						Name:  "drafts",
+						Usage: "include pages that are marked as draft",
+						Value: true,
+					},
+					&cli.BoolFlag{
+						Name:  "future",
+						Usage: "include pages whose publish date is in the future",
+						Value: true,
+					},
 				},
 				Action: liveReload,
 			},
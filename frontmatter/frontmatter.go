@@ -1,4 +1,7 @@
 // Package frontmatter provides a parser for page metadata stored in markdown code blocks.
+//
+// Besides fenced code blocks, the delimiters used by Jekyll and Hugo are supported,
+// i.e. `---` for YAML and `+++` for TOML front-matter.
 package frontmatter
 
 import (
@@ -9,6 +12,9 @@ import (
 	"io"
 	"strings"
 	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
 )
 
 // ErrUnsupportedFormat indicates that the front-matter uses an unsupported format.
@@ -23,6 +29,12 @@ var ErrBadFrontMatter = fmt.Errorf("bad front-matter")
 // Fence delimits front-matter blocks.
 const Fence = "```"
 
+// YAMLDelimiter delimits YAML front-matter blocks, as used by Jekyll and Hugo.
+const YAMLDelimiter = "---"
+
+// TOMLDelimiter delimits TOML front-matter blocks, as used by Hugo.
+const TOMLDelimiter = "+++"
+
 // SimpleDateLayout is the time.Time layout used for SimpleDate typed dates that omit a timestamp.
 const SimpleDateLayout = "2006-01-02"
 
@@ -36,10 +48,17 @@ func NewSimpleDate(year, month, day int) *SimpleDate {
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
+// Besides SimpleDateLayout, RFC 3339 timestamps are accepted as well.
+// This is required for TOML front-matter where dates are not quoted.
 func (s *SimpleDate) UnmarshalJSON(b []byte) error {
-	t, err := time.Parse(SimpleDateLayout, string(bytes.Trim(b, `"`)))
+	value := string(bytes.Trim(b, `"`))
+	t, err := time.Parse(SimpleDateLayout, value)
 	if err != nil {
-		return err
+		var rfcErr error
+		t, rfcErr = time.Parse(time.RFC3339, value)
+		if rfcErr != nil {
+			return err
+		}
 	}
 	*s = SimpleDate(t)
 	return nil
@@ -77,40 +96,101 @@ func readLine(r io.Reader) (s string, err error) {
 	}
 }
 
+// closingDelimiter returns the delimiter that ends a front-matter which started with line,
+// as well as the format of the front-matter.
+// An empty delimiter is returned if line does not start a front-matter.
+func closingDelimiter(line string) (delimiter, format string) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == YAMLDelimiter:
+		return YAMLDelimiter, "yaml"
+	case trimmed == TOMLDelimiter:
+		return TOMLDelimiter, "toml"
+	case strings.HasPrefix(line, Fence) && trimmed != Fence:
+		return Fence, strings.TrimSpace(strings.TrimPrefix(line, Fence))
+	default:
+		return "", ""
+	}
+}
+
 // Read parses a front-matter from the given reader and stores the decoded data into dest.
 // Parsing is stopped after the closing limiter of the front-matter has been read leaving
 // the given reader reusable, e.g. to read the following content.
+//
+// JSON, YAML and TOML front-matter are supported.  Independent of the format the
+// data is decoded using the `json` struct tags of dest.
 func Read(ctx context.Context, r io.Reader, dest interface{}) error {
-	var data, format string
+	line, err := readLine(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
+	}
+	delimiter, format := closingDelimiter(line)
+	if delimiter == "" {
+		// Content starts without a front-matter.
+		return ErrNoFrontMatter
+	}
 
+	var data strings.Builder
 	for {
 		line, err := readLine(r)
 		if err != nil {
-			return fmt.Errorf("%s: %w", ErrBadFrontMatter, err)
+			return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
 		}
 
-		if strings.TrimSpace(line) == Fence {
-			// Found closing fence.
+		if strings.TrimSpace(line) == delimiter {
+			// Found closing delimiter.
 			break
-		} else if strings.HasPrefix(line, Fence) {
-			// Found starting fence with format attribute.
-			format = strings.TrimSpace(strings.TrimPrefix(line, Fence))
-		} else if format == "" {
-			// Content starts without a front-matter.
-			return ErrNoFrontMatter
-		} else {
-			data += line
 		}
+		data.WriteString(line)
+		data.WriteByte('\n')
+	}
+
+	err = Decode(format, []byte(data.String()), dest)
+	if err != nil {
+		return err
 	}
 
+	return nil
+}
+
+// Decode unmarshals data of the given format, i.e. json, yaml, yml or toml, into dest.
+// YAML and TOML are decoded into a generic map first and then converted to JSON
+// such that dest only needs to provide `json` struct tags.
+func Decode(format string, data []byte, dest interface{}) error {
+	var (
+		generic map[string]interface{}
+		err     error
+	)
 	switch format {
 	case "json":
-		err := json.Unmarshal([]byte(data), &dest)
+		err = json.Unmarshal(data, &dest)
 		if err != nil {
-			return fmt.Errorf("%s: %w", ErrBadFrontMatter, err)
+			return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
 		}
 		return nil
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &generic)
+	case "toml":
+		err = toml.Unmarshal(data, &generic)
 	default:
 		return fmt.Errorf("cannot unmarshal %q front-matter: %w", format, ErrUnsupportedFormat)
 	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
+	}
+
+	if generic == nil {
+		// Empty front-matter.
+		return nil
+	}
+	converted, err := json.Marshal(generic)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
+	}
+	err = json.Unmarshal(converted, &dest)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadFrontMatter, err)
+	}
+
+	return nil
 }
@@ -57,6 +57,41 @@ func TestRead(t *testing.T) {
 		{
 			"yaml",
 			readTestContent(t, "yaml"),
+			"\n# A test page\n",
+			testFrontMatter{Author: "Andreas Linz", CreatedAt: NewSimpleDate(2021, 5, 26)},
+			nil,
+		},
+		{
+			"yaml-delimited",
+			readTestContent(t, "yaml-delimited"),
+			"\n# A test page",
+			testFrontMatter{Author: "Andreas Linz", CreatedAt: NewSimpleDate(2021, 5, 26)},
+			nil,
+		},
+		{
+			"toml",
+			readTestContent(t, "toml"),
+			"\n# A test page",
+			testFrontMatter{Author: "Andreas Linz", CreatedAt: NewSimpleDate(2021, 5, 26)},
+			nil,
+		},
+		{
+			"toml-delimited",
+			readTestContent(t, "toml-delimited"),
+			"\n# A test page",
+			testFrontMatter{Author: "Andreas Linz", CreatedAt: NewSimpleDate(2021, 5, 26)},
+			nil,
+		},
+		{
+			"bad-yaml",
+			readTestContent(t, "bad-yaml"),
+			"",
+			testFrontMatter{},
+			ErrBadFrontMatter,
+		},
+		{
+			"unsupported-format",
+			readTestContent(t, "unsupported-format"),
 			"",
 			testFrontMatter{},
 			ErrUnsupportedFormat,
@@ -104,4 +139,8 @@ func TestSimpleDate(t *testing.T) {
 	d = &SimpleDate{}
 	require.NoError(t, d.UnmarshalJSON([]byte(jsonEncoded)), "unmarshaling failed")
 	require.Equal(t, "2020-07-17", d.String())
+
+	d = &SimpleDate{}
+	require.NoError(t, d.UnmarshalJSON([]byte(`"2020-07-17T00:00:00Z"`)), "unmarshaling timestamp failed")
+	require.Equal(t, NewSimpleDate(2020, 7, 17), d)
 }
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// manifestName is the name of the build cache manifest inside the output directory.
+const manifestName = ".ssg-manifest.json"
+
+// manifestVersion must be incremented whenever the generated output changes for the same inputs,
+// e.g. if the default markdown extensions are changed.  This invalidates existing build caches.
+const manifestVersion = 2
+
+// manifest is the persisted form of the build cache.
+type manifest struct {
+	Version int `json:"version"`
+	// Outputs maps the name of every generated file to the digest of its inputs.
+	Outputs map[string]string `json:"outputs"`
+	// Warnings maps the slash-separated source paths of pages to their warnings, see Generator.Warnings.
+	// They are reported again for pages that are not generated again.
+	Warnings map[string][]string `json:"warnings,omitempty"`
+}
+
+// buildCache keeps track of the inputs of all generated files.
+// A file does not need to be generated again if the digest of its inputs did not change since the previous build.
+type buildCache struct {
+	lock sync.Mutex
+	// base is the digest of inputs that are shared by all rendered files, e.g. config, templates and menu.
+	base string
+	// previous are the output digests of the previous build.
+	previous map[string]string
+	// current are the output digests of the running build.
+	current map[string]string
+}
+
+func newBuildCache(base string, previous map[string]string) *buildCache {
+	if previous == nil {
+		previous = make(map[string]string)
+	}
+
+	return &buildCache{
+		base:     base,
+		previous: previous,
+		current:  make(map[string]string, len(previous)),
+	}
+}
+
+// matches returns true if the named output was generated by the previous build from inputs with the same digest.
+// Note that a nil cache never matches.
+func (c *buildCache) matches(name, digest string) bool {
+	if c == nil {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.previous[name] == digest
+}
+
+// update records the digest for the inputs of the named output.
+func (c *buildCache) update(name, digest string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	c.current[name] = digest
+	c.lock.Unlock()
+}
+
+// outputs returns the output digests of the current build.
+func (c *buildCache) outputs() map[string]string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	outputs := make(map[string]string, len(c.current))
+	for name, digest := range c.current {
+		outputs[name] = digest
+	}
+
+	return outputs
+}
+
+// loadManifest reads the build cache manifest from the given storage.
+// A missing or outdated manifest results in an empty manifest.
+func loadManifest(ctx context.Context, stor Storage) (*manifest, error) {
+	f, err := stor.Open(ctx, manifestName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &manifest{Version: manifestVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var m manifest
+	err = json.NewDecoder(f).Decode(&m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version != manifestVersion {
+		return &manifest{Version: manifestVersion}, nil
+	}
+
+	return &m, nil
+}
+
+// storeManifest persists the given build cache manifest.
+func storeManifest(ctx context.Context, stor Storage, m *manifest) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(json.NewEncoder(pw).Encode(m))
+	}()
+	defer pr.Close()
+
+	return stor.Store(ctx, manifestName, pr)
+}
+
+// digest returns a hex encoded SHA-256 hash of the given values.
+// Values are separated by NUL bytes such that different splits of the same bytes result in different digests.
+func digest(values ...[]byte) string {
+	h := sha256.New()
+	for _, value := range values {
+		_, _ = h.Write(value)
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// digestReader returns a hex encoded SHA-256 hash of everything read from r.
+func digestReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	_, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
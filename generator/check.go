@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrBrokenLinks indicates that the generated website contains broken internal links.
+var ErrBrokenLinks = fmt.Errorf("broken links")
+
+// BrokenLink is an internal link of a generated page that does not resolve.
+type BrokenLink struct {
+	// Page is the name of the HTML file that contains the link.
+	Page string
+	// Link is the value of the href or src attribute.
+	Link string
+	// Reason explains why the link is broken.
+	Reason string
+}
+
+// LinkReport is a list of broken links, sorted by page.
+type LinkReport []BrokenLink
+
+// String returns the report grouped by page.
+func (r LinkReport) String() string {
+	var sb strings.Builder
+	for idx, link := range r {
+		if idx == 0 || r[idx-1].Page != link.Page {
+			fmt.Fprintf(&sb, "%s\n", link.Page)
+		}
+		fmt.Fprintf(&sb, "  %s: %s\n", link.Link, link.Reason)
+	}
+
+	return sb.String()
+}
+
+// htmlDocument contains the links and element ids of a parsed HTML file.
+type htmlDocument struct {
+	links []string
+	ids   map[string]struct{}
+}
+
+// parseHTML returns all href and src attributes as well as all element ids of the given HTML document.
+func parseHTML(r io.Reader) (*htmlDocument, error) {
+	doc := &htmlDocument{ids: make(map[string]struct{})}
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				return doc, nil
+			}
+
+			return nil, tokenizer.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			for _, attr := range token.Attr {
+				switch {
+				case attr.Key == "href" || attr.Key == "src":
+					doc.links = append(doc.links, attr.Val)
+				case attr.Key == "id" || (attr.Key == "name" && token.Data == "a"):
+					doc.ids[attr.Val] = struct{}{}
+				}
+			}
+		}
+	}
+}
+
+// CheckLinks parses all HTML files of the given storage and reports internal links that do not refer
+// to an existing file, as well as fragments that do not refer to an existing element id.
+// Links are internal if they are relative, or if their host equals the host of baseURL and their path is inside
+// the path of baseURL.
+func CheckLinks(ctx context.Context, stor Storage, baseURL string) (LinkReport, error) {
+	names, err := stor.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkLinks(ctx, stor, baseURL, names)
+}
+
+// checkLinks checks the links of the named files of the given storage, see CheckLinks.
+// Links to files that are not named are reported as broken, even if they exist in storage.
+func checkLinks(ctx context.Context, stor Storage, baseURL string, names []string) (LinkReport, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("base URL %q: %w", baseURL, err)
+	}
+	files := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		files[name] = struct{}{}
+	}
+
+	docs := make(map[string]*htmlDocument)
+	for _, name := range names {
+		if path.Ext(name) != ".html" || isHidden(name) {
+			continue
+		}
+		f, err := stor.Open(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := parseHTML(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %q failed: %w", name, err)
+		}
+		docs[name] = doc
+	}
+
+	var report LinkReport
+	for name, doc := range docs {
+		for _, link := range doc.links {
+			reason := checkLink(name, link, base, files, docs)
+			if reason != "" {
+				report = append(report, BrokenLink{Page: name, Link: link, Reason: reason})
+			}
+		}
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Page != report[j].Page {
+			return report[i].Page < report[j].Page
+		}
+
+		return report[i].Link < report[j].Link
+	})
+
+	return report, nil
+}
+
+// siteURL returns the path of the given absolute URL relative to the website's root, i.e. starting with a slash.
+// The URL is external, and false is returned, if its scheme or host differ from the base URL,
+// or if its path is not inside the path of the base URL.
+func siteURL(u, base *url.URL) (string, bool) {
+	if base.Host == "" || !strings.EqualFold(u.Host, base.Host) || (u.Scheme != "" && u.Scheme != base.Scheme) {
+		return "", false
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	if u.Path != basePath && !strings.HasPrefix(u.Path, basePath+"/") {
+		return "", false
+	}
+
+	return "/" + strings.TrimPrefix(strings.TrimPrefix(u.Path, basePath), "/"), true
+}
+
+// checkLink returns the reason why the link of the named page is broken, or an empty string if it is not.
+func checkLink(name, link string, base *url.URL, files map[string]struct{}, docs map[string]*htmlDocument) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "malformed URL"
+	}
+	if u.Scheme != "" || u.Host != "" {
+		sitePath, ok := siteURL(u, base)
+		if !ok {
+			// External links are not checked.
+			return ""
+		}
+		u.Scheme, u.Host, u.Path = "", "", sitePath
+	}
+
+	target := name
+	if u.Path != "" {
+		if strings.HasPrefix(u.Path, "/") {
+			target = path.Clean(strings.TrimPrefix(u.Path, "/"))
+		} else {
+			target = path.Join(path.Dir(name), u.Path)
+		}
+		if strings.HasSuffix(u.Path, "/") || target == "." {
+			target = path.Join(target, "index.html")
+		}
+		if _, ok := files[target]; !ok {
+			if _, ok := files[path.Join(target, "index.html")]; !ok {
+				return "file not found"
+			}
+			target = path.Join(target, "index.html")
+		}
+	}
+
+	// The empty fragment and "top" refer to the top of the document.
+	if u.Fragment == "" || u.Fragment == "top" {
+		return ""
+	}
+	doc, ok := docs[target]
+	if !ok {
+		return ""
+	}
+	if _, ok := doc.ids[u.Fragment]; !ok {
+		return "anchor not found"
+	}
+
+	return ""
+}
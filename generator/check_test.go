@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLinks(t *testing.T) {
+	stor := &memoryStorage{t: t, memFS: fstest.MapFS{
+		"index.html": &fstest.MapFile{Data: []byte(`<!DOCTYPE html>
+<html><head><link rel="stylesheet" href="/static/base.css"></head>
+<body id="top-of-page">
+<a href="https://john.doe/blog/post.html#intro">ok</a>
+<a href="blog/post.html#outro">missing anchor</a>
+<a href="blog">directory</a>
+<a href="blog/">directory with slash</a>
+<a href="#top-of-page">ok</a>
+<a href="#nope">missing anchor</a>
+<a href="https://example.com/missing.html">external</a>
+<a href="mailto:john@doe">mail</a>
+<img src="images/missing.webp">
+</body></html>`)},
+		"blog/index.html": &fstest.MapFile{Data: []byte(`<a href="../index.html#top">ok</a><a href="post.html#legacy">ok</a>`)},
+		"blog/post.html": &fstest.MapFile{Data: []byte(`<h2 id="intro">Intro</h2><a name="legacy"></a>
+<a href="/blog/missing.html">missing</a><a href="../../index.html">outside</a>`)},
+		"static/base.css": &fstest.MapFile{},
+	}}
+
+	report, err := CheckLinks(context.Background(), stor, "https://john.doe")
+	require.NoError(t, err)
+	require.Equal(t, LinkReport{
+		{Page: "blog/post.html", Link: "../../index.html", Reason: "file not found"},
+		{Page: "blog/post.html", Link: "/blog/missing.html", Reason: "file not found"},
+		{Page: "index.html", Link: "#nope", Reason: "anchor not found"},
+		{Page: "index.html", Link: "blog/post.html#outro", Reason: "anchor not found"},
+		{Page: "index.html", Link: "images/missing.webp", Reason: "file not found"},
+	}, report)
+	require.Equal(t, `blog/post.html
+  ../../index.html: file not found
+  /blog/missing.html: file not found
+index.html
+  #nope: anchor not found
+  blog/post.html#outro: anchor not found
+  images/missing.webp: file not found
+`, report.String())
+}
+
+func TestSiteURL(t *testing.T) {
+	tCases := []struct {
+		name     string
+		baseURL  string
+		link     string
+		expected string
+		internal bool
+	}{
+		{"same host", "https://john.doe", "https://john.doe/blog/post.html", "/blog/post.html", true},
+		{"root", "https://john.doe", "https://john.doe", "/", true},
+		{"host case", "https://john.doe", "https://JOHN.doe/index.html", "/index.html", true},
+		{"protocol relative", "https://john.doe", "//john.doe/index.html", "/index.html", true},
+		{"other host with same prefix", "https://john.doe", "https://john.doe.example.com/index.html", "", false},
+		{"other scheme", "https://john.doe", "http://john.doe/index.html", "", false},
+		{"base path", "https://john.doe/site/", "https://john.doe/site/blog/post.html", "/blog/post.html", true},
+		{"base path root", "https://john.doe/site", "https://john.doe/site", "/", true},
+		{"outside of base path", "https://john.doe/site", "https://john.doe/sitemap.xml", "", false},
+		{"no base URL", "", "https://john.doe/index.html", "", false},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			base, err := url.Parse(tCase.baseURL)
+			require.NoError(t, err)
+			u, err := url.Parse(tCase.link)
+			require.NoError(t, err)
+			sitePath, internal := siteURL(u, base)
+			require.Equal(t, tCase.internal, internal)
+			require.Equal(t, tCase.expected, sitePath)
+		})
+	}
+}
+
+func TestGeneratorCheckLinks(t *testing.T) {
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net", CheckLinks: true}
+	generator, _ := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	require.NoError(t, generator.Run(context.Background()))
+
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Home\"}\n```\n![photo](images/missing.webp)\n"),
+		},
+	}
+	generator, _ = newTestGenerator(t, config, contentFS)
+	err := generator.Run(context.Background())
+	require.ErrorIs(t, err, ErrBrokenLinks)
+	require.ErrorContains(t, err, "index.html\n  images/missing.webp: file not found\n")
+
+	// Stale files of previous builds are not considered if pruning is disabled.
+	config.NoPrune = true
+	contentFS["index.md"].Data = []byte("```json\n{\"title\":\"Home\"}\n```\n[old](https://klingt.net/old.html)\n")
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	memStor.memFS["old.html"] = &fstest.MapFile{Data: []byte("<p>Old</p>")}
+	err = generator.Run(context.Background())
+	require.ErrorIs(t, err, ErrBrokenLinks)
+	require.ErrorContains(t, err, "index.html\n  https://klingt.net/old.html: file not found\n")
+}
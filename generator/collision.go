@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
+)
+
+// ErrDuplicateOutput indicates that multiple sources would be rendered into the same output file.
+var ErrDuplicateOutput = fmt.Errorf("duplicate output path")
+
+// outputPaths maps output files to the descriptions of the sources they are rendered from.
+type outputPaths map[string][]string
+
+func (o outputPaths) add(path, source string) {
+	o[path] = append(o[path], source)
+}
+
+func (o outputPaths) taken(path string) bool {
+	return len(o[path]) > 0
+}
+
+// err returns an error that names all sources of every duplicate output path, or nil if there are none.
+func (o outputPaths) err() error {
+	var errs []error
+	for path, sources := range o {
+		if len(sources) > 1 {
+			errs = append(errs, fmt.Errorf("%w %q: %s", ErrDuplicateOutput, path, strings.Join(sources, ", ")))
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errors.Join(errs...)
+}
+
+// pagePriority orders pages whose output paths collide.
+// Pages with an explicit URL come first, then index pages, then all other pages.
+func pagePriority(page *model.Page) int {
+	switch {
+	case page.Frontmatter().URL != "":
+		return 0
+	case filepath.Base(page.Path()) == "index.md":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// addFeeds adds the feeds of the given content in all configured formats.
+func (g *Generator) addFeeds(outputs outputPaths, content model.Tree, source string) {
+	for _, format := range g.config.FeedFormats() {
+		outputs.add(filepath.Join(content.Path(), feedFormats[format].name), source)
+	}
+}
+
+// generatedOutputs returns the output paths of all files that are copied or generated, except for pages and aliases.
+func (g *Generator) generatedOutputs(content *model.ContentTree) (outputPaths, error) {
+	outputs := outputPaths{}
+	for _, file := range model.Files(content) {
+		outputs.add(file.Path(), fmt.Sprintf("asset %s", file.Path()))
+	}
+	if g.staticFS != nil {
+		err := fs.WalkDir(g.staticFS, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				outputs.add(path, fmt.Sprintf("static file %s", path))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if g.config.Highlight != nil {
+		outputs.add(renderer.HighlightStylesheet, "highlight stylesheet")
+	}
+	if g.config.Incremental {
+		outputs.add(manifestName, "build cache manifest")
+	}
+
+	dirs, err := listDirs(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		for _, pagination := range renderer.Paginate(dir, g.config.PageSize) {
+			outputs.add(filepath.Join(pagination.Path, "index.html"), fmt.Sprintf("list of %s", dir.Path()))
+		}
+		if hasFeed(dir) {
+			g.addFeeds(outputs, dir, fmt.Sprintf("feed of %s", dir.Path()))
+		}
+	}
+
+	tags := model.Tags(content, tagsDir, g.slugifier.Slugify)
+	if len(tags) > 0 {
+		outputs.add(filepath.Join(tagsDir, "index.html"), "tags index")
+	}
+	for _, tag := range tags {
+		for _, pagination := range renderer.Paginate(tag, g.config.PageSize) {
+			outputs.add(filepath.Join(pagination.Path, "index.html"), fmt.Sprintf("tag %s", tag.Name()))
+		}
+		g.addFeeds(outputs, tag, fmt.Sprintf("feed of tag %s", tag.Name()))
+	}
+
+	if g.config.RootFeed != nil {
+		g.addFeeds(outputs, g.rootFeedContent(content), "root feed")
+	}
+	for _, format := range g.config.Redirects {
+		outputs.add(redirectFormats[format].name, fmt.Sprintf("%s redirects", format))
+	}
+
+	if g.config.BaseURL != "" {
+		urls, err := g.sitemapURLs(content, dirs)
+		if err != nil {
+			return nil, err
+		}
+		outputs.add(sitemapName, "sitemap")
+		if chunks := splitSitemap(urls, sitemapLimit); len(chunks) > 1 {
+			for idx := range chunks {
+				outputs.add(fmt.Sprintf("sitemap-%d.xml", idx+1), "sitemap")
+			}
+		}
+		if !outputs.taken(robotsName) {
+			// Unless provided by the content or static directory, see renderRobots.
+			outputs.add(robotsName, "robots.txt")
+		}
+	}
+
+	return outputs, nil
+}
+
+// resolveOutputs determines the output paths of all pages, see pagePath.
+// An error naming the colliding sources is returned if multiple pages, aliases or other generated or copied files
+// are stored into the same output file.
+// If disambiguation is enabled, colliding pages without an explicit URL get a numeric suffix instead,
+// e.g. hello-world-2.html.
+func (g *Generator) resolveOutputs(content *model.ContentTree) error {
+	g.paths = nil
+	outputs, err := g.generatedOutputs(content)
+	if err != nil {
+		return err
+	}
+
+	pages := model.Pages(content)
+	sort.SliceStable(pages, func(i, j int) bool {
+		if pagePriority(pages[i]) != pagePriority(pages[j]) {
+			return pagePriority(pages[i]) < pagePriority(pages[j])
+		}
+
+		return pages[i].Path() < pages[j].Path()
+	})
+
+	paths := make(map[string]string, len(pages))
+	for _, page := range pages {
+		dest := g.pagePath(page)
+		if outputs.taken(dest) && g.config.Disambiguate && page.Frontmatter().URL == "" {
+			base := strings.TrimSuffix(dest, ".html")
+			for n := 2; outputs.taken(dest); n++ {
+				dest = base + "-" + strconv.Itoa(n) + ".html"
+			}
+		}
+		outputs.add(dest, page.Path())
+		paths[filepath.ToSlash(page.Path())] = dest
+	}
+
+	for _, page := range pages {
+		for _, alias := range page.Frontmatter().Aliases {
+			outputs.add(renderer.URLPath(alias), fmt.Sprintf("alias %s of %s", alias, page.Path()))
+		}
+	}
+
+	err = outputs.err()
+	if err != nil {
+		return err
+	}
+	g.paths = paths
+
+	return nil
+}
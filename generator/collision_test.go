@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorDuplicateOutputs(t *testing.T) {
+	page := func(fm string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("```json\n" + fm + "\n```\n")}
+	}
+
+	tCases := []struct {
+		name         string
+		contentFS    fstest.MapFS
+		baseURL      string
+		disambiguate bool
+		err          string
+		expected     []string
+		// listed are links that are expected on the list page of the blog directory.
+		listed []string
+	}{
+		{
+			name: "slug-equivalent titles",
+			contentFS: fstest.MapFS{
+				"blog/a.md": page(`{"title":"Hello, World"}`),
+				"blog/b.md": page(`{"title":"hello world"}`),
+			},
+			err: `duplicate output path "blog/hello-world.html": blog/a.md, blog/b.md`,
+		},
+		{
+			name: "page titled index in list directory",
+			contentFS: fstest.MapFS{
+				"blog/a.md": page(`{"title":"Index"}`),
+			},
+			err: `duplicate output path "blog/index.html": list of blog, blog/a.md`,
+		},
+		{
+			name: "page titled index next to index.md",
+			contentFS: fstest.MapFS{
+				"index.md": page(`{"title":"Home"}`),
+				"about.md": page(`{"title":"Index"}`),
+			},
+			err: `duplicate output path "index.html": index.md, about.md`,
+		},
+		{
+			name: "alias of another page",
+			contentFS: fstest.MapFS{
+				"blog/a.md": page(`{"title":"A"}`),
+				"blog/b.md": page(`{"title":"B","aliases":["/blog/a.html"]}`),
+			},
+			disambiguate: true,
+			err:          `duplicate output path "blog/a.html": blog/a.md, alias /blog/a.html of blog/b.md`,
+		},
+		{
+			name: "asset",
+			contentFS: fstest.MapFS{
+				"blog/a.md":   page(`{"title":"A"}`),
+				"blog/a.html": &fstest.MapFile{Data: []byte("<html></html>")},
+			},
+			err: `duplicate output path "blog/a.html": asset blog/a.html, blog/a.md`,
+		},
+		{
+			name: "alias of feed",
+			contentFS: fstest.MapFS{
+				"blog/a.md": page(`{"title":"A","aliases":["/blog/feed.rss"]}`),
+			},
+			err: `duplicate output path "blog/feed.rss": feed of blog, alias /blog/feed.rss of blog/a.md`,
+		},
+		{
+			name: "alias of robots.txt",
+			contentFS: fstest.MapFS{
+				"about.md": page(`{"title":"About","aliases":["/robots.txt"]}`),
+			},
+			baseURL: "https://example.com",
+			err:     `duplicate output path "robots.txt": robots.txt, alias /robots.txt of about.md`,
+		},
+		{
+			name: "disambiguate",
+			contentFS: fstest.MapFS{
+				"index.md":  page(`{"title":"Home"}`),
+				"about.md":  page(`{"title":"Index"}`),
+				"blog/a.md": page(`{"title":"Hello, World"}`),
+				"blog/b.md": page(`{"title":"hello world"}`),
+				"blog/c.md": page(`{"title":"Hello World!"}`),
+				"blog/d.md": page(`{"title":"Moved","url":"/blog/hello-world.html"}`),
+			},
+			disambiguate: true,
+			expected: []string{
+				"index.html",
+				"index-2.html",
+				"blog/hello-world.html",
+				"blog/hello-world-2.html",
+				"blog/hello-world-3.html",
+				"blog/hello-world-4.html",
+			},
+			listed: []string{
+				`href="/blog/hello-world.html"`,
+				`href="/blog/hello-world-2.html"`,
+				`href="/blog/hello-world-3.html"`,
+				`href="/blog/hello-world-4.html"`,
+			},
+		},
+		{
+			name: "disambiguate asset",
+			contentFS: fstest.MapFS{
+				"blog/a.md":             page(`{"title":"Hello World"}`),
+				"blog/hello-world.html": &fstest.MapFile{Data: []byte("<html></html>")},
+			},
+			disambiguate: true,
+			expected:     []string{"blog/hello-world.html", "blog/hello-world-2.html"},
+			listed:       []string{`href="/blog/hello-world-2.html"`},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			config := &Config{Author: "Andreas Linz", BaseURL: tCase.baseURL, Disambiguate: tCase.disambiguate}
+			generator, memStor := newTestGenerator(t, config, tCase.contentFS)
+			err := generator.Run(context.Background())
+			if tCase.err != "" {
+				require.ErrorIs(t, err, ErrDuplicateOutput)
+				require.ErrorContains(t, err, tCase.err)
+				return
+			}
+			require.NoError(t, err)
+			for _, name := range tCase.expected {
+				require.Contains(t, memStor.memFS, name)
+			}
+			for _, link := range tCase.listed {
+				require.Contains(t, string(memStor.memFS["blog/index.html"].Data), link)
+			}
+		})
+	}
+}
+
+func TestResolveOutputsKeepsFrontMatter(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"a.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Hello, World\"}\n```\n")},
+		"b.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"hello world\"}\n```\n")},
+	}
+	generator, _ := newTestGenerator(t, &Config{Author: "Andreas Linz", Disambiguate: true}, contentFS)
+	content, err := model.NewContentTree(context.Background(), contentFS, ".")
+	require.NoError(t, err)
+
+	require.NoError(t, generator.resolveOutputs(content))
+	require.Equal(t, map[string]string{"a.md": "hello-world.html", "b.md": "hello-world-2.html"}, generator.paths)
+	for _, child := range content.Children() {
+		require.Empty(t, child.(*model.Page).Frontmatter().Slug)
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
 )
 
 // Config contains generator configuration values.
@@ -24,14 +28,200 @@ type Config struct {
 	TemplatesDir string `json:"templates_dir"`
 	// EnableUnsafeHTML allow embedding raw HTML snippets into markdown.
 	EnableUnsafeHTML bool `json:"unsafe_html"`
+	// PageSize is the maximum number of pages listed on a single list page, zero disables pagination.
+	PageSize int `json:"page_size"`
+	// FeedSize is the maximum number of items in a feed, zero means unlimited.
+	FeedSize int `json:"feed_size"`
+	// SummaryWords is the number of words of page excerpts.  Defaults to renderer.DefaultSummaryWords.
+	SummaryWords int `json:"summary_words"`
+	// Incremental enables the build cache, such that only files whose inputs changed are generated.
+	Incremental bool `json:"incremental"`
+	// NoPrune disables removing files from the output directory that were not generated by the current build.
+	NoPrune bool `json:"no_prune"`
+	// Drafts includes pages that are marked as draft in the build.
+	Drafts bool `json:"drafts"`
+	// Future includes pages whose publish date is in the future in the build.
+	Future bool `json:"future"`
+	// Highlight enables syntax highlighting of fenced code blocks if set.
+	Highlight *HighlightConfig `json:"highlight"`
+	// TOC enables tables of contents and heading anchors if set.
+	TOC *TOCConfig `json:"toc"`
+	// Menu configures the navigation menu, which only contains the root level if unset.
+	Menu *MenuConfig `json:"menu"`
+	// Feeds are the formats of the generated feeds, any of "rss", "atom" and "json".  Defaults to "rss".
+	Feeds []string `json:"feeds"`
+	// RootFeed enables a site-wide feed in the root directory if set.
+	RootFeed *RootFeedConfig `json:"root_feed"`
+	// Redirects are the formats of redirect files generated for page aliases, any of "netlify" and "nginx".
+	Redirects []string `json:"redirects"`
+	// Disambiguate appends a numeric suffix to pages that would otherwise overwrite another output file.
+	// Such collisions are an error if unset.
+	Disambiguate bool `json:"disambiguate"`
+	// StrictLinks fails the build if a link to a markdown file can not be resolved to a page.
+	// Unresolved links are reported as warnings if unset.
+	StrictLinks bool `json:"strict_links"`
+	// CheckLinks verifies after every build that all internal links of the generated pages resolve, see CheckLinks.
+	CheckLinks bool `json:"check_links"`
+	// Params are custom parameters that are available to the templates as .Site.Params.
+	Params map[string]interface{} `json:"params"`
+}
+
+// RootFeedConfig contains configuration values of the site-wide feed.
+type RootFeedConfig struct {
+	// Title of the feed, defaults to the author.
+	Title string `json:"title"`
+	// Sections are the directories, relative to the content dir, whose pages are included in the feed.
+	// Pages of subdirectories are included as well.  All directories are included if unset.
+	Sections []string `json:"sections"`
+	// Limit is the maximum number of items in the feed, zero means unlimited.
+	Limit int `json:"limit"`
+}
+
+// FeedFormats returns the configured feed formats or the default format if unset.
+func (c *Config) FeedFormats() []string {
+	if len(c.Feeds) == 0 {
+		return []string{FeedRSS}
+	}
+
+	return c.Feeds
+}
+
+// HighlightConfig contains syntax highlighting configuration values.
+type HighlightConfig struct {
+	// Style is the name of a chroma style, e.g. "monokai".  Defaults to renderer.DefaultHighlightStyle.
+	Style string `json:"style"`
+	// LineNumbers enables line numbers for all code blocks.  Code blocks can override this by their info string.
+	LineNumbers bool `json:"line_numbers"`
+}
+
+// StyleName returns the configured style or the default style if unset.
+func (hc *HighlightConfig) StyleName() string {
+	if hc.Style == "" {
+		return renderer.DefaultHighlightStyle
+	}
+
+	return hc.Style
+}
+
+// TOCConfig contains configuration values of tables of contents.
+type TOCConfig struct {
+	// Enabled adds a table of contents to every page, pages can opt out by "toc": false in their front-matter.
+	// If unset, only pages with "toc": true in their front-matter have a table of contents.
+	Enabled bool `json:"enabled"`
+	// MinLevel is the lowest heading level in the table of contents.  Defaults to renderer.DefaultTOCMinLevel.
+	MinLevel int `json:"min_level"`
+	// MaxLevel is the highest heading level in the table of contents.  Defaults to renderer.DefaultTOCMaxLevel.
+	MaxLevel int `json:"max_level"`
+	// Permalinks appends an anchor linking the heading to every heading.
+	Permalinks bool `json:"permalinks"`
+}
+
+// Options returns the renderer options of the configuration.
+// Without a configuration tables of contents are disabled by default, but pages can still enable them.
+func (tc *TOCConfig) Options() renderer.TOCOptions {
+	if tc == nil {
+		return renderer.TOCOptions{}
+	}
+
+	return renderer.TOCOptions{
+		MinLevel:   tc.MinLevel,
+		MaxLevel:   tc.MaxLevel,
+		Enabled:    tc.Enabled,
+		Permalinks: tc.Permalinks,
+	}
+}
+
+// MenuConfig contains configuration values of the navigation menu.
+type MenuConfig struct {
+	// Depth is the number of directory levels in the menu.  Defaults to 1, i.e. only the root level.
+	Depth int `json:"depth"`
+	// Links are additional entries of the root level, e.g. links to external websites.
+	Links []MenuLinkConfig `json:"links"`
+}
+
+// MenuLinkConfig is an additional menu entry.
+type MenuLinkConfig struct {
+	// Title of the menu entry (required).
+	Title string `json:"title"`
+	// URL the menu entry links to (required).
+	URL string `json:"url"`
+	// Weight determines the position of the entry, entries with lower weights come first.
+	Weight int `json:"weight"`
+}
+
+// Options returns the menu options of the configuration.
+// A nil configuration results in the default options.
+func (mc *MenuConfig) Options() model.MenuOptions {
+	if mc == nil {
+		return model.MenuOptions{}
+	}
+
+	options := model.MenuOptions{Depth: mc.Depth}
+	for _, link := range mc.Links {
+		options.Links = append(options.Links, model.MenuEntry{
+			Title:    link.Title,
+			Link:     link.URL,
+			Weight:   link.Weight,
+			External: true,
+		})
+	}
+
+	return options
 }
 
 var (
 	ErrAuthorUnset     = fmt.Errorf("author is unset")
 	ErrContentDirUnset = fmt.Errorf("content dir is unset")
 	ErrOutputDirUnset  = fmt.Errorf("output dir is unset")
+	ErrNegativeSize    = fmt.Errorf("size must not be negative")
+	ErrUnknownStyle    = fmt.Errorf("unknown highlight style")
+	ErrUnknownFeed     = fmt.Errorf("unknown feed format")
+	ErrNonLocalPath    = fmt.Errorf("path must be relative and inside its directory")
+	ErrUnknownRedirect = fmt.Errorf("unknown redirect format")
+	ErrBadHeadingLevel = fmt.Errorf("heading level must be between 1 and 6")
+	ErrBadMenuLink     = fmt.Errorf("menu link requires a title and an URL")
+	ErrOutputOverlaps  = fmt.Errorf("output dir must not be or contain a source directory")
 )
 
+// realPath returns the absolute path of dir, with symbolic links resolved if dir exists.
+func realPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return abs, nil
+	}
+
+	return resolved, nil
+}
+
+// checkOutputDir returns an error if the output dir equals or contains one of the source directories.
+// Pruning would otherwise remove the sources, since they are not produced by the build.
+func (c *Config) checkOutputDir() error {
+	outputDir, err := realPath(c.OutputDir)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{c.ContentDir, c.StaticDir, c.TemplatesDir} {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		sourceDir, err := realPath(dir)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, sourceDir)
+		if err == nil && filepath.IsLocal(rel) {
+			return fmt.Errorf("output dir %q contains %q: %w", c.OutputDir, dir, ErrOutputOverlaps)
+		}
+	}
+
+	return nil
+}
+
 // Validate returns an error if the configuration is incomplete or invalid.
 func (c *Config) Validate() error {
 	if strings.TrimSpace(c.Author) == "" {
@@ -53,6 +243,69 @@ func (c *Config) Validate() error {
 	if err != nil {
 		return fmt.Errorf("bad output dir %q: %w", c.OutputDir, err)
 	}
+	err = c.checkOutputDir()
+	if err != nil {
+		return err
+	}
+
+	if c.PageSize < 0 {
+		return fmt.Errorf("page size %d: %w", c.PageSize, ErrNegativeSize)
+	}
+	if c.FeedSize < 0 {
+		return fmt.Errorf("feed size %d: %w", c.FeedSize, ErrNegativeSize)
+	}
+	if c.SummaryWords < 0 {
+		return fmt.Errorf("summary words %d: %w", c.SummaryWords, ErrNegativeSize)
+	}
+
+	if c.Highlight != nil && !renderer.IsHighlightStyle(c.Highlight.StyleName()) {
+		return fmt.Errorf("%w %q", ErrUnknownStyle, c.Highlight.Style)
+	}
+
+	if c.TOC != nil {
+		for _, level := range []int{c.TOC.MinLevel, c.TOC.MaxLevel} {
+			if level < 0 || level > 6 {
+				return fmt.Errorf("toc level %d: %w", level, ErrBadHeadingLevel)
+			}
+		}
+		if c.TOC.MinLevel != 0 && c.TOC.MaxLevel != 0 && c.TOC.MinLevel > c.TOC.MaxLevel {
+			return fmt.Errorf("toc min level %d exceeds max level %d: %w", c.TOC.MinLevel, c.TOC.MaxLevel, ErrBadHeadingLevel)
+		}
+	}
+
+	if c.Menu != nil {
+		if c.Menu.Depth < 0 {
+			return fmt.Errorf("menu depth %d: %w", c.Menu.Depth, ErrNegativeSize)
+		}
+		for _, link := range c.Menu.Links {
+			if strings.TrimSpace(link.Title) == "" || strings.TrimSpace(link.URL) == "" {
+				return fmt.Errorf("menu link %q: %w", link.Title, ErrBadMenuLink)
+			}
+		}
+	}
+
+	for _, format := range c.Feeds {
+		if _, ok := feedFormats[format]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownFeed, format)
+		}
+	}
+
+	for _, format := range c.Redirects {
+		if _, ok := redirectFormats[format]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownRedirect, format)
+		}
+	}
+
+	if c.RootFeed != nil {
+		if c.RootFeed.Limit < 0 {
+			return fmt.Errorf("root feed limit %d: %w", c.RootFeed.Limit, ErrNegativeSize)
+		}
+		for _, section := range c.RootFeed.Sections {
+			if !filepath.IsLocal(section) {
+				return fmt.Errorf("root feed section %q: %w", section, ErrNonLocalPath)
+			}
+		}
+	}
 
 	return nil
 }
@@ -20,6 +20,37 @@ func TestExampleConfig(t *testing.T) {
 		OutputDir:        "./output",
 		TemplatesDir:     "/optional/templates",
 		EnableUnsafeHTML: true,
+		PageSize:         20,
+		FeedSize:         50,
+		SummaryWords:     70,
+		Incremental:      true,
+		StrictLinks:      true,
+		CheckLinks:       true,
+		Highlight: &HighlightConfig{
+			Style:       "monokai",
+			LineNumbers: true,
+		},
+		TOC: &TOCConfig{
+			Enabled:    true,
+			MinLevel:   2,
+			MaxLevel:   4,
+			Permalinks: true,
+		},
+		Menu: &MenuConfig{
+			Depth: 2,
+			Links: []MenuLinkConfig{{Title: "GitHub", URL: "https://github.com/johndoe", Weight: 10}},
+		},
+		Feeds: []string{"rss", "atom", "json"},
+		RootFeed: &RootFeedConfig{
+			Title:    "John Doe's articles and notes",
+			Sections: []string{"articles", "notes"},
+			Limit:    20,
+		},
+		Redirects: []string{"netlify", "nginx"},
+		Params: map[string]interface{}{
+			"github":  "johndoe",
+			"tagline": "Notes on software and music",
+		},
 	})
 }
 
@@ -41,6 +72,91 @@ func TestConfigValidate(t *testing.T) {
 			nil,
 		},
 		{"no author", &Config{ContentDir: contentDir}, ErrAuthorUnset},
+		{
+			"output dir is content dir",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: contentDir},
+			ErrOutputOverlaps,
+		},
+		{
+			"output dir contains content dir",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: tDir},
+			ErrOutputOverlaps,
+		},
+		{
+			"output dir contains templates dir",
+			&Config{
+				Author:       "John Doe",
+				ContentDir:   contentDir,
+				OutputDir:    outputDir,
+				TemplatesDir: filepath.Join(outputDir, "templates"),
+			},
+			ErrOutputOverlaps,
+		},
+		{
+			"static dir inside content dir",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, StaticDir: filepath.Join(contentDir, "static")},
+			nil,
+		},
+		{
+			"negative page size",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, PageSize: -1},
+			ErrNegativeSize,
+		},
+		{
+			"default highlight style",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, Highlight: &HighlightConfig{}},
+			nil,
+		},
+		{
+			"unknown highlight style",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, Highlight: &HighlightConfig{Style: "nope"}},
+			ErrUnknownStyle,
+		},
+		{
+			"toc level out of range",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, TOC: &TOCConfig{MaxLevel: 7}},
+			ErrBadHeadingLevel,
+		},
+		{
+			"toc min level exceeds max level",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, TOC: &TOCConfig{MinLevel: 4, MaxLevel: 2}},
+			ErrBadHeadingLevel,
+		},
+		{
+			"negative menu depth",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, Menu: &MenuConfig{Depth: -1}},
+			ErrNegativeSize,
+		},
+		{
+			"menu link without url",
+			&Config{
+				Author:     "John Doe",
+				ContentDir: contentDir,
+				OutputDir:  outputDir,
+				Menu:       &MenuConfig{Links: []MenuLinkConfig{{Title: "GitHub"}}},
+			},
+			ErrBadMenuLink,
+		},
+		{
+			"unknown feed format",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, Feeds: []string{"rss", "rdf"}},
+			ErrUnknownFeed,
+		},
+		{
+			"negative root feed limit",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, RootFeed: &RootFeedConfig{Limit: -1}},
+			ErrNegativeSize,
+		},
+		{
+			"root feed section outside of content dir",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, RootFeed: &RootFeedConfig{Sections: []string{"../articles"}}},
+			ErrNonLocalPath,
+		},
+		{
+			"unknown redirect format",
+			&Config{Author: "John Doe", ContentDir: contentDir, OutputDir: outputDir, Redirects: []string{"apache"}},
+			ErrUnknownRedirect,
+		},
 		{"no content dir", &Config{Author: "John Doe"}, ErrContentDirUnset},
 		{
 			"bad content dir",
@@ -0,0 +1,229 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/feeds"
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
+)
+
+// Supported feed formats, see Config.Feeds.
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// ErrBadSourceDateEpoch indicates that the SOURCE_DATE_EPOCH environment variable is not a UNIX timestamp.
+var ErrBadSourceDateEpoch = fmt.Errorf("SOURCE_DATE_EPOCH is not a UNIX timestamp")
+
+// jsonFeedVersion is the version of the JSON Feed specification, see https://www.jsonfeed.org/version/1.1/.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// feedFormat describes how a feed is stored.
+type feedFormat struct {
+	// name of the feed file inside the list directory.
+	name string
+	// title is a human readable name of the format, used in discovery links.
+	title string
+	// mediaType of the feed file, used in discovery links.
+	mediaType string
+	// write encodes the feed, feedURL is the absolute URL of the feed file itself.
+	write func(w io.Writer, feed *feeds.Feed, feedURL string) error
+}
+
+var feedFormats = map[string]feedFormat{
+	FeedRSS: {
+		name:      "feed.rss",
+		title:     "RSS",
+		mediaType: "application/rss+xml",
+		write: func(w io.Writer, feed *feeds.Feed, _ string) error {
+			return feed.WriteRss(w)
+		},
+	},
+	FeedAtom: {
+		name:      "feed.atom",
+		title:     "Atom",
+		mediaType: "application/atom+xml",
+		write: func(w io.Writer, feed *feeds.Feed, _ string) error {
+			return feed.WriteAtom(w)
+		},
+	},
+	FeedJSON: {
+		name:      "feed.json",
+		title:     "JSON Feed",
+		mediaType: "application/feed+json",
+		write:     writeJSONFeed,
+	},
+}
+
+// jsonFeed extends the JSON Feed 1.0 representation of gorilla/feeds by the fields of version 1.1.
+type jsonFeed struct {
+	*feeds.JSONFeed
+	Authors []*feeds.JSONAuthor `json:"authors,omitempty"`
+}
+
+// writeJSONFeed writes the given feed in JSON Feed 1.1 format.
+func writeJSONFeed(w io.Writer, feed *feeds.Feed, feedURL string) error {
+	jf := (&feeds.JSON{Feed: feed}).JSONFeed()
+	jf.Version = jsonFeedVersion
+	jf.FeedUrl = feedURL
+
+	out := jsonFeed{JSONFeed: jf}
+	if jf.Author != nil {
+		out.Authors = []*feeds.JSONAuthor{jf.Author}
+	}
+	for _, item := range jf.Items {
+		if item.Id == "" {
+			// The id is required, the URL is a unique fallback.
+			item.Id = item.Url
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+// uuidNamespaceURL is the namespace for name based UUIDs of URLs as defined by RFC 4122.
+var uuidNamespaceURL = [16]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+// feedItemID returns a stable and globally unique identifier for the page of the given source path.
+// The identifier is a name based UUID (version 5) of the source path's URL, such that it does not change
+// if the page is renamed by changing its title.
+func feedItemID(baseURL, path string) string {
+	h := sha1.New()
+	_, _ = h.Write(uuidNamespaceURL[:])
+	_, _ = h.Write([]byte(renderer.AbsLink(baseURL, path)))
+	sum := h.Sum(nil)
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// buildTime returns the current time, or the time set by the SOURCE_DATE_EPOCH environment variable
+// which makes builds reproducible, see https://reproducible-builds.org/specs/source-date-epoch/.
+func buildTime() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now().UTC(), nil
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrBadSourceDateEpoch, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// hasFeed returns true if feeds are generated for the given list content.
+// The root directory has no feed of its own, see rootFeedContent.
+func hasFeed(content model.Tree) bool {
+	return content.Path() != "."
+}
+
+// rootFeed is the content of the site-wide feed, which aggregates the pages of multiple sections.
+type rootFeed struct {
+	title string
+	pages []model.Tree
+}
+
+func (rf *rootFeed) Children() []model.Tree {
+	return rf.pages
+}
+
+func (rf *rootFeed) Path() string {
+	return "."
+}
+
+func (rf *rootFeed) Name() string {
+	return rf.title
+}
+
+func (rf *rootFeed) Walk(fn func(tree model.Tree) error) error {
+	err := fn(rf)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range rf.pages {
+		err = page.Walk(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inSections returns true if dir is one of the given sections, or a subdirectory of them.
+// Every directory except the root directory is part of a section if no sections are given.
+func inSections(dir string, sections []string) bool {
+	if dir == "." {
+		return false
+	}
+	if len(sections) == 0 {
+		return true
+	}
+
+	for _, section := range sections {
+		section = filepath.Clean(section)
+		if dir == section || strings.HasPrefix(dir, section+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rootFeedContent returns the pages of all sections selected by the root feed configuration.
+// Index pages are excluded since they are the list pages of their directory.
+func (g *Generator) rootFeedContent(content *model.ContentTree) *rootFeed {
+	rf := &rootFeed{title: g.config.RootFeed.Title}
+	if rf.title == "" {
+		rf.title = g.config.Author
+	}
+
+	for _, page := range model.Pages(content) {
+		if filepath.Base(page.Path()) != "index.md" && inSections(filepath.Dir(page.Path()), g.config.RootFeed.Sections) {
+			rf.pages = append(rf.pages, page)
+		}
+	}
+
+	return rf
+}
+
+// listFeedLinks returns discovery links for the feeds of a list page, followed by the site-wide feeds.
+func (g *Generator) listFeedLinks(content model.Tree) []renderer.FeedLink {
+	if !hasFeed(content) {
+		return g.siteFeeds
+	}
+
+	return append(g.feedLinks(content), g.siteFeeds...)
+}
+
+// feedLinks returns discovery links for the feeds of the given content in all configured formats.
+func (g *Generator) feedLinks(content model.Tree) []renderer.FeedLink {
+	var links []renderer.FeedLink
+	for _, format := range g.config.FeedFormats() {
+		links = append(links, renderer.FeedLink{
+			Title: model.Title(content) + " (" + feedFormats[format].title + ")",
+			Type:  feedFormats[format].mediaType,
+			Href:  renderer.AbsLink(g.config.BaseURL, filepath.Join(content.Path(), feedFormats[format].name)),
+		})
+	}
+
+	return links
+}
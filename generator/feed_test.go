@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorFeeds(t *testing.T) {
+	config := &Config{
+		Author:  "Andreas Linz",
+		BaseURL: "https://klingt.net",
+		Feeds:   []string{FeedRSS, FeedAtom, FeedJSON},
+	}
+	generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	err := generator.Run(context.Background())
+	require.NoError(t, err)
+
+	for _, name := range []string{"blog/feed.rss", "tags/go/feed.rss", "tags/go/feed.atom", "tags/go/feed.json"} {
+		_, err := memStor.memFS.ReadFile(name)
+		require.NoError(t, err, name)
+	}
+
+	data, err := memStor.memFS.ReadFile("blog/feed.atom")
+	require.NoError(t, err)
+	var atom struct {
+		XMLName xml.Name
+		Entries []struct {
+			Title string `xml:"title"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &atom))
+	require.Equal(t, "feed", atom.XMLName.Local)
+	require.Len(t, atom.Entries, 2)
+
+	data, err = memStor.memFS.ReadFile("blog/feed.json")
+	require.NoError(t, err)
+	var jf struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Authors []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+		Items []struct {
+			ID  string `json:"id"`
+			URL string `json:"url"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(data, &jf))
+	require.Equal(t, jsonFeedVersion, jf.Version)
+	require.Equal(t, "https://klingt.net/blog/feed.json", jf.FeedURL)
+	require.Len(t, jf.Authors, 1)
+	require.Equal(t, "Andreas Linz", jf.Authors[0].Name)
+	require.Len(t, jf.Items, 2)
+	for _, item := range jf.Items {
+		require.NotEmpty(t, item.ID)
+		require.NotEmpty(t, item.URL)
+	}
+
+	// Note that html/template escapes the plus sign of media types.
+	list, err := memStor.memFS.ReadFile("blog/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(list), `<link rel="alternate" type="application/rss&#43;xml" title="Blog (RSS)" href="https://klingt.net/blog/feed.rss" />`)
+	require.Contains(t, string(list), `<link rel="alternate" type="application/atom&#43;xml" title="Blog (Atom)" href="https://klingt.net/blog/feed.atom" />`)
+	require.Contains(t, string(list), `<link rel="alternate" type="application/feed&#43;json" title="Blog (JSON Feed)" href="https://klingt.net/blog/feed.json" />`)
+}
+
+func TestFeedItemID(t *testing.T) {
+	// Generated by Python's uuid.uuid5(uuid.NAMESPACE_URL, "https://klingt.net/blog/first.md").
+	require.Equal(t, "urn:uuid:dbd46f03-ced3-55c1-bdfe-d198fad1af85", feedItemID("https://klingt.net", "blog/first.md"))
+}
+
+func TestGeneratorFeedsReproducible(t *testing.T) {
+	config := &Config{
+		Author:  "Andreas Linz",
+		BaseURL: "https://klingt.net",
+		Feeds:   []string{FeedRSS, FeedAtom, FeedJSON},
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	var builds []map[string][]byte
+	for i := 0; i < 2; i++ {
+		generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+		require.NoError(t, generator.Run(context.Background()))
+
+		feeds := make(map[string][]byte)
+		for _, name := range []string{"blog/feed.rss", "blog/feed.atom", "blog/feed.json"} {
+			data, err := memStor.memFS.ReadFile(name)
+			require.NoError(t, err)
+			feeds[name] = data
+		}
+		builds = append(builds, feeds)
+	}
+	require.Equal(t, builds[0], builds[1])
+
+	rss := string(builds[0]["blog/feed.rss"])
+	require.Contains(t, rss, "<pubDate>Tue, 14 Nov 2023 22:13:20 +0000</pubDate>")
+	require.Contains(t, rss, "<guid>urn:uuid:dbd46f03-ced3-55c1-bdfe-d198fad1af85</guid>")
+	require.Contains(t, rss, "<pubDate>Sun, 19 Dec 2021 00:00:00 +0000</pubDate>")
+	require.Contains(t, rss, "<lastBuildDate>Sun, 02 Jan 2022 00:00:00 +0000</lastBuildDate>")
+	require.Less(t, strings.Index(rss, "First Article"), strings.Index(rss, "Second Article"))
+	require.Contains(t, string(builds[0]["blog/feed.json"]), `"date_modified": "2022-01-02T00:00:00Z"`)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	generator, _ := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	require.ErrorIs(t, generator.Run(context.Background()), ErrBadSourceDateEpoch)
+}
+
+func TestGeneratorRootFeed(t *testing.T) {
+	page := func(title, date string, hidden bool) *fstest.MapFile {
+		return &fstest.MapFile{
+			Data: []byte(fmt.Sprintf("```json\n{\"title\":%q,\"created_at\":%q,\"hidden\":%t}\n```\n", title, date, hidden)),
+		}
+	}
+	contentFS := fstest.MapFS{
+		"index.md":                page("Home", "2023-01-01", false),
+		"about.md":                {Data: []byte("```json\n{\"title\":\"About\",\"tags\":[\"go\"]}\n```\n")},
+		"articles/hello.md":       page("Hello", "2023-01-02", false),
+		"articles/bye.md":         page("Bye", "2023-01-05", false),
+		"articles/draft.md":       page("Draft", "2023-01-06", true),
+		"articles/2023/deep.md":   page("Deep", "2023-01-03", false),
+		"notes/index.md":          page("Notes", "2023-01-07", false),
+		"notes/something-else.md": page("Something else", "2023-01-04", false),
+	}
+
+	tCases := []struct {
+		name     string
+		config   *RootFeedConfig
+		expected []string
+	}{
+		{
+			name:     "all sections",
+			config:   &RootFeedConfig{},
+			expected: []string{"Bye", "Something else", "Deep", "Hello"},
+		},
+		{
+			name:     "limit",
+			config:   &RootFeedConfig{Limit: 2},
+			expected: []string{"Bye", "Something else"},
+		},
+		{
+			name:     "selected sections",
+			config:   &RootFeedConfig{Sections: []string{"articles/2023", "notes"}},
+			expected: []string{"Something else", "Deep"},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			config := &Config{
+				Author:   "Andreas Linz",
+				BaseURL:  "https://klingt.net",
+				RootFeed: tCase.config,
+			}
+			generator, memStor := newTestGenerator(t, config, contentFS)
+			require.NoError(t, generator.Run(context.Background()))
+
+			data, err := memStor.memFS.ReadFile("feed.rss")
+			require.NoError(t, err)
+			var rss struct {
+				Title string `xml:"channel>title"`
+				Items []struct {
+					Title string `xml:"title"`
+				} `xml:"channel>item"`
+			}
+			require.NoError(t, xml.Unmarshal(data, &rss))
+			require.Equal(t, "Andreas Linz", rss.Title)
+			var titles []string
+			for _, item := range rss.Items {
+				titles = append(titles, item.Title)
+			}
+			require.Equal(t, tCase.expected, titles)
+
+			about, err := memStor.memFS.ReadFile("about.html")
+			require.NoError(t, err)
+			require.Contains(t, string(about), `title="Andreas Linz (RSS)" href="https://klingt.net/feed.rss"`)
+			tags, err := memStor.memFS.ReadFile("tags/index.html")
+			require.NoError(t, err)
+			require.Contains(t, string(tags), `title="Andreas Linz (RSS)" href="https://klingt.net/feed.rss"`)
+		})
+	}
+}
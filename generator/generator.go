@@ -5,12 +5,13 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -36,6 +37,9 @@ func DefaultTemplateFS() fs.FS {
 //go:embed static
 var defaultStaticFS embed.FS
 
+// tagsDir is the directory where tag pages are stored into.
+const tagsDir = "tags"
+
 type Generator struct {
 	concurrency        int
 	sourceFS, staticFS fs.FS
@@ -44,16 +48,135 @@ type Generator struct {
 	renderer           renderer.Renderer
 	config             *Config
 	bufPool            *sync.Pool
+	// cache is the build cache of the running build, nil if incremental builds are disabled.
+	cache *buildCache
+	// manifest contains the output digests and warnings of the last build.
+	manifest *manifest
+	// produced contains the names of all files produced by the last build.
+	produced *outputSet
+	// buildTime is the time of the running build, see buildTime.
+	buildTime time.Time
+	// siteFeeds are links to the site-wide feeds, which are linked by every page.
+	siteFeeds []renderer.FeedLink
+	// paths maps the slash-separated source paths of all pages to their output paths, see resolveOutputs.
+	paths map[string]string
+	// redirects are the redirects of page aliases, see Redirects.
+	redirects []Redirect
+	// warnings of the running build by the slash-separated source path of the page they refer to, see Warnings.
+	warnings   map[string]map[string]struct{}
+	warningsMu sync.Mutex
 }
 
-func (g *Generator) copyStaticFiles(ctx context.Context) error {
-	cp := func(ctx context.Context, path string) error {
-		src, err := g.staticFS.Open(path)
+// store persists content under the given name and records it as produced by the running build.
+func (g *Generator) store(ctx context.Context, name string, content io.Reader) error {
+	err := g.stor.Store(ctx, name, content)
+	if err != nil {
+		return err
+	}
+	g.produced.add(name)
+
+	return nil
+}
+
+// isFresh returns true if the named output does not need to be generated again
+// because it already exists and its inputs did not change since the previous build.
+func (g *Generator) isFresh(ctx context.Context, name, digest string) bool {
+	if !g.cache.matches(name, digest) {
+		return false
+	}
+
+	f, err := g.stor.Open(ctx, name)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	g.cache.update(name, digest)
+	g.produced.add(name)
+
+	return true
+}
+
+// storeCached stores content, if necessary, and records the digest of its inputs in the build cache.
+// Content is only stored if the name is not fresh.  Use this for files that are copied as is.
+func (g *Generator) storeCached(ctx context.Context, name string, open func() (io.ReadCloser, error)) error {
+	var inputDigest string
+	if g.cache != nil {
+		f, err := open()
 		if err != nil {
 			return err
 		}
-		defer src.Close()
-		return g.stor.Store(ctx, path, src)
+		inputDigest, err = digestReader(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if g.isFresh(ctx, name, inputDigest) {
+			return nil
+		}
+	}
+
+	f, err := open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = g.store(ctx, name, f)
+	if err != nil {
+		return err
+	}
+	g.cache.update(name, inputDigest)
+
+	return nil
+}
+
+// storeBytes stores data under the given name, unless it is unchanged since the previous build.
+func (g *Generator) storeBytes(ctx context.Context, name string, data []byte) error {
+	return g.storeCached(ctx, name, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// pageDigest returns a digest of all inputs of the given page, including its navigation.
+func (g *Generator) pageDigest(page *model.Page, navigation renderer.Navigation) (string, error) {
+	fm, err := json.Marshal(page.Frontmatter())
+	if err != nil {
+		return "", err
+	}
+	nav, err := g.navigationDigest(navigation)
+	if err != nil {
+		return "", err
+	}
+
+	return digest([]byte(g.cache.base), []byte(page.Path()), fm, page.Content(), nav), nil
+}
+
+// listDigest returns a digest of all inputs of the list page and feed of content.
+func (g *Generator) listDigest(content model.Tree) (string, error) {
+	values := [][]byte{[]byte(g.cache.base), []byte(content.Path())}
+	if dir, ok := content.(*model.ContentTree); ok {
+		meta, err := json.Marshal(dir.Meta())
+		if err != nil {
+			return "", err
+		}
+		values = append(values, meta)
+	}
+	for _, page := range renderer.ListPages(content) {
+		fm, err := json.Marshal(page.FM)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, []byte(page.Path), fm, page.Markdown)
+	}
+
+	return digest(values...), nil
+}
+
+func (g *Generator) copyStaticFiles(ctx context.Context) error {
+	cp := func(ctx context.Context, path string) error {
+		return g.storeCached(ctx, path, func() (io.ReadCloser, error) {
+			return g.staticFS.Open(path)
+		})
 	}
 
 	return distribute.OneToN(
@@ -85,77 +208,149 @@ func (g *Generator) renderListPage(
 	content model.Tree,
 	siteMenu []model.MenuEntry,
 ) error {
+	var inputDigest string
+	if g.cache != nil {
+		var err error
+		inputDigest, err = g.listDigest(content)
+		if err != nil {
+			return err
+		}
+	}
+
 	buf := g.bufPool.Get().(*bytes.Buffer)
-	buf.Reset()
 	defer g.bufPool.Put(buf)
 
-	err := g.renderer.List(ctx, buf, content, siteMenu)
-	if err != nil {
-		return err
+	for _, pagination := range renderer.Paginate(content, g.config.PageSize) {
+		dest := filepath.Join(pagination.Path, "index.html")
+		if g.isFresh(ctx, dest, inputDigest) {
+			continue
+		}
+
+		buf.Reset()
+		err := g.renderer.List(
+			ctx,
+			buf,
+			content,
+			pagination,
+			g.listFeedLinks(content),
+			model.ActiveMenu(siteMenu, content.Path()),
+		)
+		if err != nil {
+			return err
+		}
+
+		err = g.store(ctx, dest, buf)
+		if err != nil {
+			return err
+		}
+		g.cache.update(dest, inputDigest)
 	}
 
-	return g.stor.Store(ctx, filepath.Join(content.Path(), "index.html"), buf)
+	return nil
 }
 
-func (g *Generator) renderFeed(ctx context.Context, content model.Tree) error {
-	if content.Path() == "." {
-		// Ignore root dir.
-		return nil
+// renderFeed renders the feeds of the given content in all configured formats.
+// The number of feed items is limited to limit, unless it is zero.
+func (g *Generator) renderFeed(ctx context.Context, content model.Tree, limit int) error {
+	formats := g.config.FeedFormats()
+	var inputDigest string
+	if g.cache != nil {
+		var err error
+		inputDigest, err = g.listDigest(content)
+		if err != nil {
+			return err
+		}
+		fresh := true
+		for _, format := range formats {
+			fresh = g.isFresh(ctx, filepath.Join(content.Path(), feedFormats[format].name), inputDigest) && fresh
+		}
+		if fresh {
+			return nil
+		}
 	}
 
-	feed, err := g.buildFeed(ctx, content)
+	feed, err := g.buildFeed(ctx, content, limit)
 	if err != nil {
 		return err
 	}
 
+	for _, format := range formats {
+		dest := filepath.Join(content.Path(), feedFormats[format].name)
+		err = g.storeFeed(ctx, dest, feed, feedFormats[format])
+		if err != nil {
+			return err
+		}
+		g.cache.update(dest, inputDigest)
+	}
+
+	return nil
+}
+
+// storeFeed stores the feed in the given format.
+func (g *Generator) storeFeed(ctx context.Context, dest string, feed *feeds.Feed, format feedFormat) error {
 	pr, pw := io.Pipe()
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
 		defer pw.Close()
-		return feed.WriteRss(pw)
+		return format.write(pw, feed, renderer.AbsLink(g.config.BaseURL, dest))
 	})
 	eg.Go(func() error {
 		defer pr.Close()
-		return g.stor.Store(ctx, filepath.Join(content.Path(), "feed.rss"), pr)
+		return g.store(ctx, dest, pr)
 	})
 
 	return eg.Wait()
 }
 
-func (g *Generator) buildFeed(ctx context.Context, content model.Tree) (*feeds.Feed, error) {
+func (g *Generator) buildFeed(ctx context.Context, content model.Tree, limit int) (*feeds.Feed, error) {
 	feed := &feeds.Feed{
 		Title:   content.Name(),
 		Link:    &feeds.Link{Href: renderer.AbsLink(g.config.BaseURL, content.Path())},
 		Author:  &feeds.Author{Name: g.config.Author},
-		Created: time.Now(),
+		Created: g.buildTime,
+	}
+	if dir, ok := content.(*model.ContentTree); ok {
+		if meta := dir.Meta(); meta.Title != "" {
+			feed.Title = meta.Title
+		}
+		feed.Description = dir.Meta().Description
 	}
-	feedLock := sync.RWMutex{}
 
-	concurrency := len(content.Children())
+	// Feed items are always the newest pages, regardless of the sort order of the directory.
+	pages := renderer.ListPages(content)
+	renderer.SortPages(pages)
+	if limit > 0 && len(pages) > limit {
+		pages = pages[:limit]
+	}
+	if updated := newestDate(pages); updated != nil {
+		feed.Updated = time.Time(*updated)
+	}
+	feed.Items = make([]*feeds.Item, len(pages))
+
+	concurrency := len(pages)
 	if g.concurrency < concurrency {
 		concurrency = g.concurrency
 	}
+	if concurrency == 0 {
+		return feed, nil
+	}
 	err := distribute.OneToN(
 		ctx,
 		func(ctx context.Context, dataCh chan<- interface{}) error {
-			for _, child := range content.Children() {
-				page, ok := child.(*model.Page)
-				if ok && !page.Frontmatter().Hidden {
-					dataCh <- page
-				}
+			for idx := range pages {
+				dataCh <- idx
 			}
 
 			return nil
 		},
 		func(ctx context.Context, data interface{}) error {
-			page := data.(*model.Page)
-			item, err := g.renderFeedPage(ctx, page)
+			idx := data.(int)
+			item, err := g.renderFeedPage(ctx, pages[idx])
 			if err != nil {
 				return err
 			}
-			feedLock.Lock()
-			feed.Items = append(feed.Items, item)
-			feedLock.Unlock()
+			// Every worker writes to a distinct index, hence no locking is required.
+			feed.Items[idx] = item
 
 			return nil
 		},
@@ -165,55 +360,80 @@ func (g *Generator) buildFeed(ctx context.Context, content model.Tree) (*feeds.F
 	return feed, err
 }
 
-func (g *Generator) renderFeedPage(ctx context.Context, page *model.Page) (*feeds.Item, error) {
+func (g *Generator) renderFeedPage(ctx context.Context, page renderer.TemplatePage) (*feeds.Item, error) {
 	buf := g.bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	defer g.bufPool.Put(buf)
 
-	templatePage := renderer.NewTemplatePage(page)
-	err := g.renderer.FeedPage(ctx, buf, templatePage)
+	err := g.renderer.FeedPage(ctx, buf, page)
 	if err != nil {
 		return nil, err
 	}
 
-	return &feeds.Item{
-		Title:       page.Frontmatter().Title,
-		Description: page.Frontmatter().Description,
-		Author:      &feeds.Author{Name: page.Frontmatter().Author},
+	item := &feeds.Item{
+		Id:          feedItemID(g.config.BaseURL, page.Path),
+		Title:       page.FM.Title,
+		Description: page.Description(),
+		Author:      &feeds.Author{Name: page.FM.Author},
 		Link: &feeds.Link{
-			Href: renderer.PageLink(g.config.BaseURL, g.slugifier, templatePage),
+			Href: renderer.AbsLink(g.config.BaseURL, g.outputPath(page)),
 		},
-		Created: time.Now(),
 		Content: buf.String(),
-	}, nil
+	}
+	if page.FM.CreatedAt != nil {
+		item.Created = time.Time(*page.FM.CreatedAt)
+	}
+	if page.FM.UpdatedAt != nil {
+		item.Updated = time.Time(*page.FM.UpdatedAt)
+	}
+
+	return item, nil
 }
 
 func (g *Generator) renderPage(
 	ctx context.Context,
-	content model.Tree,
 	siteMenu []model.MenuEntry,
-	page *model.Page,
+	job pageJob,
 ) error {
-	var dest string
-	if strings.HasSuffix(page.Path(), "index.md") {
-		dest = filepath.Join(filepath.Dir(page.Path()), "index.html")
-	} else {
-		dest = filepath.Join(filepath.Dir(page.Path()), g.slugifier.Slugify(page.Frontmatter().Title)) + ".html"
+	page := job.page
+	dest := g.pagePath(page)
+	navigation := g.navigation(job)
+
+	var inputDigest string
+	if g.cache != nil {
+		var err error
+		inputDigest, err = g.pageDigest(page, navigation)
+		if err != nil {
+			return err
+		}
+		if g.isFresh(ctx, dest, inputDigest) {
+			g.replayWarnings(page)
+			return nil
+		}
 	}
 
 	buf := g.bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	defer g.bufPool.Put(buf)
 
-	err := g.renderer.Page(ctx, buf, renderer.NewTemplatePage(page), siteMenu)
+	err := g.renderer.Page(
+		ctx,
+		buf,
+		renderer.NewTemplatePage(page),
+		navigation,
+		g.siteFeeds,
+		model.ActiveMenu(siteMenu, page.Path()),
+	)
 	if err != nil {
 		return err
 	}
 
-	err = g.stor.Store(ctx, dest, buf)
+	err = g.store(ctx, dest, buf)
 	if err != nil {
 		return err
 	}
+	g.cache.update(dest, inputDigest)
+
 	return nil
 }
 
@@ -223,13 +443,10 @@ func (g *Generator) copyStatic(ctx context.Context, content *model.ContentTree)
 		if !ok {
 			return nil
 		}
-		f, err := g.sourceFS.Open(file.Path())
-		if err != nil {
-			return err
-		}
-		defer f.Close()
 
-		return g.stor.Store(ctx, file.Path(), f)
+		return g.storeCached(ctx, file.Path(), func() (io.ReadCloser, error) {
+			return g.sourceFS.Open(file.Path())
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("copying asset files failed: %w", err)
@@ -242,78 +459,168 @@ func (g *Generator) copyStatic(ctx context.Context, content *model.ContentTree)
 		}
 	}
 
+	if g.config.Highlight != nil {
+		err = g.storeHighlightCSS(ctx)
+		if err != nil {
+			return fmt.Errorf("storing highlight stylesheet failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func (g *Generator) collectContentDirs(
+// storeHighlightCSS stores the stylesheet for syntax highlighted code blocks.
+func (g *Generator) storeHighlightCSS(ctx context.Context) error {
+	buf := bytes.NewBuffer(nil)
+	err := renderer.HighlightCSS(buf, g.config.Highlight.StyleName())
+	if err != nil {
+		return err
+	}
+
+	return g.storeBytes(ctx, renderer.HighlightStylesheet, buf.Bytes())
+}
+
+// isListDir returns true if the given directory contains pages but no index.md,
+// i.e. if a list page and feed are generated for it.
+func isListDir(content *model.ContentTree) bool {
+	var containsIndexMD, containsPages bool
+	for _, child := range content.Children() {
+		page, ok := child.(*model.Page)
+		if !ok || filepath.Ext(page.Path()) != ".md" {
+			continue
+		}
+
+		if filepath.Base(page.Path()) == "index.md" {
+			containsIndexMD = true
+		} else {
+			containsPages = true
+		}
+	}
+
+	return containsPages && !containsIndexMD
+}
+
+// listDirs returns all directories of the content tree that require a list page.
+func listDirs(content *model.ContentTree) ([]*model.ContentTree, error) {
+	var dirs []*model.ContentTree
+	err := content.Walk(func(tree model.Tree) error {
+		dir, ok := tree.(*model.ContentTree)
+		if ok && isListDir(dir) {
+			dirs = append(dirs, dir)
+		}
+
+		return nil
+	})
+
+	return dirs, err
+}
+
+// renderList renders the list page and feed for the given content.
+func (g *Generator) renderList(ctx context.Context, content model.Tree, siteMenu []model.MenuEntry) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		if !hasFeed(content) {
+			return nil
+		}
+
+		err := g.renderFeed(ctx, content, g.config.FeedSize)
+		if err != nil {
+			return fmt.Errorf("feed rendering failed: %w", err)
+		}
+
+		return nil
+	})
+	eg.Go(func() error {
+		return g.renderListPage(ctx, content, siteMenu)
+	})
+
+	return eg.Wait()
+}
+
+// renderTags renders an overview of all tags as well as a list page and feed for every tag.
+func (g *Generator) renderTags(
 	ctx context.Context,
-	tree model.Tree,
-	resultCh chan<- interface{},
+	content *model.ContentTree,
+	siteMenu []model.MenuEntry,
 ) error {
-	content, ok := tree.(*model.ContentTree)
-	if !ok {
+	tags := model.Tags(content, tagsDir, g.slugifier.Slugify)
+	if len(tags) == 0 {
 		return nil
 	}
 
-	var containsIndexMD, containsPages bool
-	for _, child := range tree.Children() {
-		switch el := child.(type) {
-		case *model.ContentTree:
-			err := g.collectContentDirs(ctx, el, resultCh)
-			if err != nil {
-				return err
-			}
-		case *model.Page:
-			ext := filepath.Ext(el.Path())
-			if ext != ".md" {
-				continue
-			}
+	err := g.renderTagsIndex(ctx, tags, siteMenu)
+	if err != nil {
+		return err
+	}
 
-			if el.Name() == "index.md" {
-				containsIndexMD = true
-			} else {
-				containsPages = true
+	return distribute.OneToN(
+		ctx,
+		func(ctx context.Context, dataCh chan<- interface{}) error {
+			for _, tag := range tags {
+				dataCh <- tag
 			}
+
+			return nil
+		},
+		func(ctx context.Context, data interface{}) error {
+			return g.renderList(ctx, data.(*model.Tag), siteMenu)
+		},
+		g.concurrency,
+	)
+}
+
+// renderTagsIndex renders the overview of all tags.
+func (g *Generator) renderTagsIndex(ctx context.Context, tags []*model.Tag, siteMenu []model.MenuEntry) error {
+	dest := filepath.Join(tagsDir, "index.html")
+	var inputDigest string
+	if g.cache != nil {
+		values := [][]byte{[]byte(g.cache.base)}
+		for _, tag := range tags {
+			values = append(values, []byte(tag.Path()), []byte(tag.Name()), []byte(strconv.Itoa(tag.Count())))
+		}
+		inputDigest = digest(values...)
+		if g.isFresh(ctx, dest, inputDigest) {
+			return nil
 		}
 	}
-	if containsPages && !containsIndexMD {
-		resultCh <- content
+
+	buf := g.bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer g.bufPool.Put(buf)
+
+	err := g.renderer.Tags(ctx, buf, tags, g.siteFeeds, siteMenu)
+	if err != nil {
+		return err
 	}
+	err = g.store(ctx, dest, buf)
+	if err != nil {
+		return err
+	}
+	g.cache.update(dest, inputDigest)
 
 	return nil
 }
 
-func (g *Generator) render(ctx context.Context, content *model.ContentTree) error {
-	rootMenu := model.Menu(content)
-
-	err := distribute.OneToN(
+func (g *Generator) render(
+	ctx context.Context,
+	content *model.ContentTree,
+	rootMenu []model.MenuEntry,
+) error {
+	dirs, err := listDirs(content)
+	if err != nil {
+		return fmt.Errorf("rendering list pages failed: %w", err)
+	}
+	err = distribute.OneToN(
 		ctx,
 		func(ctx context.Context, dataCh chan<- interface{}) error {
-			err := content.Walk(func(tree model.Tree) error {
-				return g.collectContentDirs(ctx, tree, dataCh)
-			})
-			if err != nil {
-				return fmt.Errorf("rendering list pages failed: %w", err)
+			for _, dir := range dirs {
+				dataCh <- dir
 			}
 
 			return nil
 		},
 		func(ctx context.Context, data interface{}) error {
-			content := data.(*model.ContentTree)
-			eg, ctx := errgroup.WithContext(ctx)
-			eg.Go(func() error {
-				err := g.renderFeed(ctx, content)
-				if err != nil {
-					return fmt.Errorf("feed rendering failed: %w", err)
-				}
-
-				return nil
-			})
-			eg.Go(func() error {
-				return g.renderListPage(ctx, content, rootMenu)
-			})
-
-			return eg.Wait()
+			return g.renderList(ctx, data.(*model.ContentTree), rootMenu)
 		},
 		g.concurrency,
 	)
@@ -321,42 +628,237 @@ func (g *Generator) render(ctx context.Context, content *model.ContentTree) erro
 		return err
 	}
 
+	err = g.renderTags(ctx, content, rootMenu)
+	if err != nil {
+		return fmt.Errorf("rendering tag pages failed: %w", err)
+	}
+
+	if g.config.RootFeed != nil {
+		err = g.renderFeed(ctx, g.rootFeedContent(content), g.config.RootFeed.Limit)
+		if err != nil {
+			return fmt.Errorf("rendering root feed failed: %w", err)
+		}
+	}
+
+	err = g.renderRedirects(ctx)
+	if err != nil {
+		return fmt.Errorf("rendering redirects failed: %w", err)
+	}
+
+	if g.config.BaseURL != "" {
+		// Sitemaps require absolute URLs.
+		err = g.renderSitemap(ctx, content, dirs)
+		if err != nil {
+			return fmt.Errorf("rendering sitemap failed: %w", err)
+		}
+		err = g.renderRobots(ctx)
+		if err != nil {
+			return fmt.Errorf("rendering robots.txt failed: %w", err)
+		}
+	}
+
 	return distribute.OneToN(
 		ctx,
 		func(ctx context.Context, dataCh chan<- interface{}) error {
-			return content.Walk(func(tree model.Tree) error {
-				page, ok := tree.(*model.Page)
-				if ok {
-					dataCh <- page
-				}
+			return walkPages(content, nil, func(job pageJob) error {
+				dataCh <- job
 
 				return nil
 			})
 		},
 		func(ctx context.Context, data interface{}) error {
-			page := data.(*model.Page)
-			return g.renderPage(ctx, content, rootMenu, page)
+			return g.renderPage(ctx, rootMenu, data.(pageJob))
 		},
 		g.concurrency,
 	)
 }
 
+// summarize sets the summarizer of all pages, see renderer.Renderer.Summarize.
+// Summaries are derived when they are first requested, i.e. not for pages and lists that are not generated again.
+func (g *Generator) summarize(content *model.ContentTree) {
+	summarizer := func(page *model.Page) model.Summary {
+		return g.renderer.Summarize(renderer.TemplatePage{Path: page.Path(), Markdown: page.Content()}, g.config.SummaryWords)
+	}
+	for _, page := range model.Pages(content) {
+		page.SetSummarizer(summarizer)
+	}
+}
+
+// pagePath returns the output path of the given page, see outputPath.
+func (g *Generator) pagePath(page *model.Page) string {
+	return g.outputPath(renderer.NewTemplatePage(page))
+}
+
+// outputPath returns the output path of the given page as determined by resolveOutputs.
+// Pages that are not resolved yet are rendered to their path according to renderer.PagePath.
+func (g *Generator) outputPath(page renderer.TemplatePage) string {
+	dest, ok := g.paths[filepath.ToSlash(page.Path)]
+	if ok {
+		return dest
+	}
+
+	return renderer.PagePath(g.slugifier, page)
+}
+
+// isPublished returns true if the page is part of the build.
+// Drafts and pages that are scheduled to be published after the build time are excluded, unless enabled by the config.
+func (g *Generator) isPublished(page *model.Page) bool {
+	fm := page.Frontmatter()
+	if fm.Draft && !g.config.Drafts {
+		return false
+	}
+	if fm.PublishAt != nil && time.Time(*fm.PublishAt).After(g.buildTime) && !g.config.Future {
+		return false
+	}
+
+	return true
+}
+
 // Run generates the website.
 func (g *Generator) Run(ctx context.Context) error {
 	content, err := model.NewContentTree(ctx, g.sourceFS, ".")
 	if err != nil {
 		return fmt.Errorf("library initialization failed: %w", err)
 	}
+	g.buildTime, err = buildTime()
+	if err != nil {
+		return err
+	}
+	content = content.Filter(g.isPublished)
+	err = g.resolveOutputs(content)
+	if err != nil {
+		return fmt.Errorf("resolving output paths failed: %w", err)
+	}
+	g.summarize(content)
+	site := renderer.NewSite(
+		g.config.Author,
+		g.config.BaseURL,
+		g.config.Params,
+		content,
+		model.Tags(content, tagsDir, g.slugifier.Slugify),
+		g.pagePath,
+	)
+	ctx = renderer.WithSite(ctx, site)
+	rootMenu := model.Menu(content, g.pagePath, g.config.Menu.Options())
+	g.redirects = g.pageRedirects(content)
+	ctx = renderer.WithLinks(ctx, renderer.NewLinks(g.config.BaseURL, g.paths, g.unresolvedLink))
+	g.warnings = make(map[string]map[string]struct{})
+
+	g.siteFeeds = nil
+	if g.config.RootFeed != nil {
+		g.siteFeeds = g.feedLinks(g.rootFeedContent(content))
+	}
+	g.produced = newOutputSet()
+	g.cache = nil
+	if g.config.Incremental {
+		err = g.initCache(ctx, rootMenu, g.paths, site)
+		if err != nil {
+			return fmt.Errorf("build cache initialization failed: %w", err)
+		}
+	}
 
 	err = g.copyStatic(ctx, content)
 	if err != nil {
 		return fmt.Errorf("copying static content failed: %w", err)
 	}
 
-	err = g.render(ctx, content)
+	err = g.render(ctx, content, rootMenu)
 	if err != nil {
 		return fmt.Errorf("rendering failed: %w", err)
 	}
+
+	if g.cache != nil {
+		g.manifest = &manifest{
+			Version:  manifestVersion,
+			Outputs:  g.cache.outputs(),
+			Warnings: g.pageWarnings(),
+		}
+		err = storeManifest(ctx, g.stor, g.manifest)
+		if err != nil {
+			return fmt.Errorf("storing build cache failed: %w", err)
+		}
+	}
+
+	if !g.config.NoPrune {
+		err = g.prune(ctx)
+		if err != nil {
+			return fmt.Errorf("pruning stale files failed: %w", err)
+		}
+	}
+
+	if g.config.CheckLinks {
+		// Only files of this build are considered, stale files might still exist if pruning is disabled.
+		report, err := checkLinks(ctx, g.stor, g.config.BaseURL, g.produced.list())
+		if err != nil {
+			return fmt.Errorf("checking links failed: %w", err)
+		}
+		if len(report) > 0 {
+			return fmt.Errorf("%w:\n%s", ErrBrokenLinks, report)
+		}
+	}
+
+	return nil
+}
+
+// initCache prepares the build cache for an incremental build.
+// The outputs of the previous build are loaded from storage, unless they are already known from a previous run.
+// Pages are generated again if the output path of any page changes, since they might link to it.
+// If the templates access the site data, every file is generated again whenever any page changes.
+func (g *Generator) initCache(
+	ctx context.Context,
+	rootMenu []model.MenuEntry,
+	linkPaths map[string]string,
+	site *renderer.Site,
+) error {
+	if g.manifest == nil {
+		manifest, err := loadManifest(ctx, g.stor)
+		if err != nil {
+			return err
+		}
+		g.manifest = manifest
+	}
+
+	config, err := json.Marshal(g.config)
+	if err != nil {
+		return err
+	}
+	menu, err := json.Marshal(rootMenu)
+	if err != nil {
+		return err
+	}
+	links, err := json.Marshal(linkPaths)
+	if err != nil {
+		return err
+	}
+	values := [][]byte{
+		[]byte(strconv.Itoa(manifestVersion)),
+		config,
+		[]byte(g.renderer.Fingerprint()),
+		menu,
+		links,
+	}
+	if g.renderer.UsesSite() {
+		// Include hidden pages as well, since they are part of the site's content tree.
+		err = site.Content.Walk(func(tree model.Tree) error {
+			page, ok := tree.(*model.Page)
+			if !ok {
+				return nil
+			}
+			fm, err := json.Marshal(page.Frontmatter())
+			if err != nil {
+				return err
+			}
+			values = append(values, []byte(page.Path()), fm, page.Content())
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	base := digest(values...)
+	g.cache = newBuildCache(base, g.manifest.Outputs)
+
 	return nil
 }
 
@@ -39,6 +39,21 @@ func (ds *DiscardStorage) Store(ctx context.Context, name string, content io.Rea
 	return
 }
 
+// Open implements Storage.
+func (ds *DiscardStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return nil, fs.ErrNotExist
+}
+
+// List implements Storage.
+func (ds *DiscardStorage) List(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// Remove implements Storage.
+func (ds *DiscardStorage) Remove(ctx context.Context, name string) error {
+	return fs.ErrNotExist
+}
+
 func (ds *DiscardStorage) reset() {
 	ds.lock.Lock()
 	ds.N = 0
@@ -110,6 +125,8 @@ func newBenchContentFS(b *testing.B, depth, pages int) fs.FS {
 		}
 
 		filename := fmt.Sprintf("%spage%05d.md", prefix, i)
+		// Titles must be unique, since pages with the same title in the same directory collide.
+		fm.Title = fmt.Sprintf("%s %d", b.Name(), i)
 		contentFS[filename] = &fstest.MapFile{
 			Data: buildPage(b, string(pageContent), fm),
 		}
@@ -126,7 +143,10 @@ func BenchmarkGenerator(b *testing.B) {
 		Author:  b.Name(),
 		BaseURL: "https://does.not.matter",
 	}
-	templates := renderer.NewTemplates(config.Author, config.BaseURL, sl, DefaultTemplateFS())
+	templates, err := renderer.NewTemplates(config.Author, config.BaseURL, sl, DefaultTemplateFS(), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
 	generator := New(
 		config,
 		newBenchContentFS(b, 10, 1000),
@@ -145,8 +165,8 @@ func BenchmarkGenerator(b *testing.B) {
 				if err != nil {
 					b.Fatal(err.Error())
 				}
-				if ds.calls() != 1131 {
-					b.Fatalf("not enough pages rendered, expected %d but was %d", 1131, ds.calls())
+				if ds.calls() != 1030 {
+					b.Fatalf("not enough pages rendered, expected %d but was %d", 1030, ds.calls())
 				}
 			}
 		})
@@ -1,14 +1,17 @@
 package generator
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"io/fs"
+	"strings"
 	"sync"
 	"testing"
 	"testing/fstest"
 	"time"
 
+	"github.com/klingtnet/static-site-generator/generator/model"
 	"github.com/klingtnet/static-site-generator/generator/renderer"
 	"github.com/klingtnet/static-site-generator/internal/testutils"
 	"github.com/klingtnet/static-site-generator/slug"
@@ -40,31 +43,79 @@ func (ms *memoryStorage) Store(_ context.Context, name string, content io.Reader
 	return nil
 }
 
-func TestGenerator(t *testing.T) {
-	// Idea:
-	// If this setup is used multiple times inside tests then
-	// the generator setup could be moved into a testutil function
-	// with the following signature:
-	// NewTestGenerator(*testing.T, sourceFS, staticFS fs.FS, storage Storage)
-	config := &Config{
-		Author:  "Andreas Linz",
-		BaseURL: "https://klingt.net",
-	}
-	contentFS := testutils.NewTestContentFS(t)
+func newTestGenerator(t *testing.T, config *Config, contentFS fs.FS) (*Generator, *memoryStorage) {
 	memStor := &memoryStorage{t: t, memFS: make(fstest.MapFS)}
 	slugifier := slug.NewSlugifier('-')
-	templates := renderer.NewTemplates(
+	templates, err := renderer.NewTemplates(
 		config.Author,
 		config.BaseURL,
 		slugifier,
 		DefaultTemplateFS(),
+		nil,
 	)
+	require.NoError(t, err)
+	extensions := []goldmark.Extender{
+		extension.GFM,
+		emoji.Emoji,
+		extension.Footnote,
+		renderer.TableOfContents(config.TOC.Options()),
+	}
+	var stylesheets []string
+	if config.Highlight != nil {
+		extensions = append(extensions, renderer.Highlighting(config.Highlight.StyleName(), config.Highlight.LineNumbers))
+		stylesheets = append(stylesheets, renderer.HighlightStylesheet)
+	}
 	renderer := renderer.NewMarkdown(
-		goldmark.New(goldmark.WithExtensions(extension.GFM, emoji.Emoji, extension.Footnote)),
+		goldmark.New(goldmark.WithExtensions(extensions...)),
 		templates,
+		stylesheets...,
 	)
 
-	generator := New(config, contentFS, nil, memStor, slugifier, renderer)
+	return New(config, contentFS, nil, memStor, slugifier, renderer), memStor
+}
+
+func (ms *memoryStorage) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	f, ok := ms.memFS[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+
+	return io.NopCloser(bytes.NewReader(f.Data)), nil
+}
+
+func (ms *memoryStorage) List(_ context.Context) ([]string, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	names := make([]string, 0, len(ms.memFS))
+	for name := range ms.memFS {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (ms *memoryStorage) Remove(_ context.Context, name string) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	if _, ok := ms.memFS[name]; !ok {
+		return fs.ErrNotExist
+	}
+	delete(ms.memFS, name)
+
+	return nil
+}
+
+func TestGenerator(t *testing.T) {
+	config := &Config{
+		Author:  "Andreas Linz",
+		BaseURL: "https://klingt.net",
+	}
+	generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
 	err := generator.Run(context.Background())
 	require.NoError(t, err)
 
@@ -86,6 +137,9 @@ func TestGenerator(t *testing.T) {
 		"blog",
 		"files",
 		"static",
+		"tags",
+		"tags/go",
+		"tags/testing",
 	}, folders)
 	require.ElementsMatch(t, []string{
 		"about.html",
@@ -96,6 +150,13 @@ func TestGenerator(t *testing.T) {
 		"blog/index.html",
 		"blog/first-article.html",
 		"blog/second-article.html",
+		"tags/index.html",
+		"tags/go/index.html",
+		"tags/go/feed.rss",
+		"tags/testing/index.html",
+		"tags/testing/feed.rss",
+		"sitemap.xml",
+		"robots.txt",
 	}, files)
 
 	// TODO:
@@ -104,3 +165,532 @@ func TestGenerator(t *testing.T) {
 	// Golden files had the advantage of being easy to edit and review but come with the disadvantage of being tedious to maintain.
 	// Hashes are opaque but easy to generate and maintain.
 }
+
+func TestGeneratorPagination(t *testing.T) {
+	config := &Config{
+		Author:   "Andreas Linz",
+		BaseURL:  "https://klingt.net",
+		PageSize: 1,
+		FeedSize: 1,
+	}
+	generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	err := generator.Run(context.Background())
+	require.NoError(t, err)
+
+	first, err := memStor.memFS.ReadFile("blog/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(first), "https://klingt.net/blog/page/2")
+	second, err := memStor.memFS.ReadFile("blog/page/2/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(second), "https://klingt.net/blog")
+	require.Contains(t, string(second), "2/2")
+
+	feed, err := memStor.memFS.ReadFile("blog/feed.rss")
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(feed), "<item>"))
+}
+
+func TestListDirs(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md":          &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Home\"}\n```\n")},
+		"blog/first.md":     &fstest.MapFile{Data: []byte("```json\n{\"title\":\"First\"}\n```\n")},
+		"blog/2023/next.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Next\"}\n```\n")},
+		"docs/index.md":     &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Docs\"}\n```\n")},
+		"docs/setup.md":     &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Setup\"}\n```\n")},
+		"files/random.txt":  &fstest.MapFile{Data: []byte("random")},
+	}
+	content, err := model.NewContentTree(context.Background(), contentFS, ".")
+	require.NoError(t, err)
+
+	dirs, err := listDirs(content)
+	require.NoError(t, err)
+	var paths []string
+	for _, dir := range dirs {
+		paths = append(paths, dir.Path())
+	}
+	// Every directory is listed once, even if it is nested.
+	require.Equal(t, []string{"blog", "blog/2023"}, paths)
+}
+
+func TestGeneratorHighlight(t *testing.T) {
+	config := &Config{
+		Author:    "Andreas Linz",
+		BaseURL:   "https://klingt.net",
+		Highlight: &HighlightConfig{Style: "monokai"},
+	}
+	contentFS := fstest.MapFS{
+		"code.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Code\",\"created_at\":\"2023-07-01\"}\n```\n\n```go\nfunc main() {}\n```\n"),
+		},
+	}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	err := generator.Run(context.Background())
+	require.NoError(t, err)
+
+	page, err := memStor.memFS.ReadFile("code.html")
+	require.NoError(t, err)
+	require.Contains(t, string(page), `<pre class="chroma">`)
+	require.Contains(t, string(page), "https://klingt.net/static/highlight.css")
+
+	css, err := memStor.memFS.ReadFile(renderer.HighlightStylesheet)
+	require.NoError(t, err)
+	require.Contains(t, string(css), ".chroma")
+}
+
+func TestGeneratorDrafts(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"blog/published.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Published\",\"created_at\":\"2023-01-01\",\"tags\":[\"go\"]}\n```\n"),
+		},
+		"blog/draft.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Draft\",\"created_at\":\"2023-01-02\",\"tags\":[\"go\"],\"draft\":true}\n```\n"),
+		},
+		"blog/scheduled.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Scheduled\",\"created_at\":\"2023-01-03\",\"publish_at\":\"2023-06-01T12:00:00Z\"}\n```\n"),
+		},
+		"notes/draft.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Note\",\"draft\":true}\n```\n"),
+		},
+	}
+	// The scheduled page is due at 2023-06-01T12:00:00Z, i.e. 1685620800 seconds since the UNIX epoch.
+	tCases := []struct {
+		name        string
+		drafts      bool
+		future      bool
+		sourceDate  string
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:        "published only",
+			sourceDate:  "1685620799",
+			expected:    []string{"blog/published.html"},
+			notExpected: []string{"blog/draft.html", "blog/scheduled.html", "notes/note.html", "notes/index.html"},
+		},
+		{
+			name:        "scheduled page is due",
+			sourceDate:  "1685620800",
+			expected:    []string{"blog/published.html", "blog/scheduled.html"},
+			notExpected: []string{"blog/draft.html", "notes/note.html"},
+		},
+		{
+			name:        "drafts",
+			drafts:      true,
+			sourceDate:  "1685620799",
+			expected:    []string{"blog/published.html", "blog/draft.html", "notes/note.html"},
+			notExpected: []string{"blog/scheduled.html"},
+		},
+		{
+			name:        "future",
+			future:      true,
+			sourceDate:  "1685620799",
+			expected:    []string{"blog/published.html", "blog/scheduled.html"},
+			notExpected: []string{"blog/draft.html", "notes/note.html"},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			t.Setenv("SOURCE_DATE_EPOCH", tCase.sourceDate)
+			config := &Config{
+				Author:  "Andreas Linz",
+				BaseURL: "https://klingt.net",
+				Drafts:  tCase.drafts,
+				Future:  tCase.future,
+			}
+			generator, memStor := newTestGenerator(t, config, contentFS)
+			require.NoError(t, generator.Run(context.Background()))
+
+			for _, name := range tCase.expected {
+				require.Contains(t, memStor.memFS, name)
+			}
+			for _, name := range tCase.notExpected {
+				require.NotContains(t, memStor.memFS, name)
+			}
+
+			// Excluded pages must neither be listed nor be part of feeds, tags or the sitemap.
+			for _, name := range []string{"blog/index.html", "blog/feed.rss", "tags/go/index.html", "sitemap.xml"} {
+				data, err := memStor.memFS.ReadFile(name)
+				require.NoError(t, err, name)
+				require.Equal(t, tCase.drafts, strings.Contains(string(data), "blog/draft.html"), name)
+			}
+			menu, err := memStor.memFS.ReadFile("blog/published.html")
+			require.NoError(t, err)
+			require.Equal(t, tCase.drafts, strings.Contains(string(menu), "https://klingt.net/notes"))
+		})
+	}
+}
+
+func TestGeneratorPathOverrides(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Home\"}\n```\n"),
+		},
+		"about.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"About Me\",\"slug\":\"about\"}\n```\n"),
+		},
+		"blog/post.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Post\",\"created_at\":\"2023-01-01\",\"url\":\"/2023/hello/\"}\n```\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	require.Contains(t, memStor.memFS, "about.html")
+	require.Contains(t, memStor.memFS, "2023/hello/index.html")
+	require.NotContains(t, memStor.memFS, "about-me.html")
+	require.NotContains(t, memStor.memFS, "blog/post.html")
+
+	index, err := memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(index), "https://klingt.net/about.html")
+	for _, name := range []string{"blog/index.html", "blog/feed.rss", "sitemap.xml"} {
+		data, err := memStor.memFS.ReadFile(name)
+		require.NoError(t, err, name)
+		require.Contains(t, string(data), "https://klingt.net/2023/hello/index.html", name)
+	}
+
+	contentFS["blog/unsafe.md"] = &fstest.MapFile{
+		Data: []byte("```json\n{\"title\":\"Unsafe\",\"url\":\"../unsafe.html\"}\n```\n"),
+	}
+	generator, _ = newTestGenerator(t, config, contentFS)
+	require.ErrorIs(t, generator.Run(context.Background()), model.ErrUnsafePath)
+}
+
+func TestGeneratorIncremental(t *testing.T) {
+	contentFS := fstest.MapFS{}
+	err := fs.WalkDir(testutils.NewTestContentFS(t), ".", func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(testutils.NewTestContentFS(t), path)
+		require.NoError(t, err)
+		contentFS[path] = &fstest.MapFile{Data: data}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	config := &Config{
+		Author:      "Andreas Linz",
+		BaseURL:     "https://klingt.net",
+		Incremental: true,
+	}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+	require.Contains(t, memStor.memFS, manifestName)
+
+	modTimes := make(map[string]time.Time)
+	for name, f := range memStor.memFS {
+		modTimes[name] = f.ModTime
+	}
+
+	// Change a single article, this must only affect the article itself, its list page and feed.
+	// The adjacent article is not affected since its navigation only depends on the title and link of the article.
+	contentFS["blog/second.md"].Data = append(contentFS["blog/second.md"].Data, []byte("\n\nAn update.")...)
+	// Start from scratch to ensure that the manifest is loaded from storage.
+	generator, _ = newTestGenerator(t, config, contentFS)
+	generator.stor = memStor
+	require.NoError(t, generator.Run(context.Background()))
+
+	var changed []string
+	for name, f := range memStor.memFS {
+		if !f.ModTime.Equal(modTimes[name]) {
+			changed = append(changed, name)
+		}
+	}
+	require.ElementsMatch(t, []string{
+		manifestName,
+		"blog/second-article.html",
+		"blog/index.html",
+		"blog/feed.rss",
+		"tags/go/index.html",
+		"tags/go/feed.rss",
+	}, changed)
+}
+
+func TestGeneratorPrune(t *testing.T) {
+	config := &Config{
+		Author:  "Andreas Linz",
+		BaseURL: "https://klingt.net",
+		NoPrune: true,
+	}
+	generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	_, err := generator.Stale(context.Background())
+	require.ErrorIs(t, err, ErrNoBuild)
+
+	memStor.memFS["blog/renamed-article.html"] = &fstest.MapFile{}
+	memStor.memFS[".git/HEAD"] = &fstest.MapFile{}
+
+	require.NoError(t, generator.Run(context.Background()))
+	stale, err := generator.Stale(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"blog/renamed-article.html"}, stale)
+	require.Contains(t, memStor.memFS, "blog/renamed-article.html")
+
+	config.NoPrune = false
+	require.NoError(t, generator.Run(context.Background()))
+	require.NotContains(t, memStor.memFS, "blog/renamed-article.html")
+	require.Contains(t, memStor.memFS, ".git/HEAD")
+	stale, err = generator.Stale(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, stale)
+}
+
+func TestGeneratorLinks(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Home\"}\n```\nRead [the post](blog/post.md#intro) and [nothing](blog/missing.md).\n"),
+		},
+		"blog/post.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"A Post\",\"created_at\":\"2023-01-01\"}\n```\nBack [home](../index.md).\n"),
+		},
+	}
+
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+	require.Equal(t, []string{`page "index.md": unresolved link "blog/missing.md"`}, generator.Warnings())
+
+	index, err := memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(index), `<a href="https://klingt.net/blog/a-post.html#intro">the post</a>`)
+	require.Contains(t, string(index), `<a href="blog/missing.md">nothing</a>`)
+	post, err := memStor.memFS.ReadFile("blog/a-post.html")
+	require.NoError(t, err)
+	require.Contains(t, string(post), `<a href="https://klingt.net/index.html">home</a>`)
+	feed, err := memStor.memFS.ReadFile("blog/feed.rss")
+	require.NoError(t, err)
+	require.Contains(t, string(feed), `https://klingt.net/index.html`)
+
+	// Incremental builds report the warnings of pages that are not generated again.
+	config.Incremental = true
+	generator, memStor = newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+	modTime := memStor.memFS["index.html"].ModTime
+	generator, _ = newTestGenerator(t, config, contentFS)
+	generator.stor = memStor
+	require.NoError(t, generator.Run(context.Background()))
+	require.Equal(t, modTime, memStor.memFS["index.html"].ModTime)
+	require.Equal(t, []string{`page "index.md": unresolved link "blog/missing.md"`}, generator.Warnings())
+	config.Incremental = false
+
+	config.StrictLinks = true
+	generator, _ = newTestGenerator(t, config, contentFS)
+	err = generator.Run(context.Background())
+	require.ErrorIs(t, err, renderer.ErrUnresolvedLink)
+	require.ErrorContains(t, err, `page "index.md": unresolved link "blog/missing.md"`)
+
+	// Links to disambiguated pages resolve to their disambiguated output path.
+	contentFS["blog/other.md"] = &fstest.MapFile{Data: []byte("```json\n{\"title\":\"A Post\",\"created_at\":\"2023-01-02\"}\n```\n")}
+	config = &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net", Disambiguate: true}
+	generator, memStor = newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+	index, err = memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(index), `<a href="https://klingt.net/blog/a-post-2.html#intro">the post</a>`)
+}
+
+func TestGeneratorTOC(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Home\"}\n```\n## Intro\n\n### Details\n\n## Usage\n"),
+		},
+		"about.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"About\",\"toc\":false}\n```\n## Intro\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", TOC: &TOCConfig{Enabled: true}}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	index, err := memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(index), `<nav class="toc">`)
+	require.Contains(t, string(index), `<li><a href="#details">Details</a></li>`)
+	require.Contains(t, string(index), `<h2 id="usage">Usage</h2>`)
+	about, err := memStor.memFS.ReadFile("about.html")
+	require.NoError(t, err)
+	require.NotContains(t, string(about), `<nav class="toc">`)
+	require.Contains(t, string(about), `<h2 id="intro">Intro</h2>`)
+
+	// Without a toc section, pages enable the table of contents by their front-matter.
+	contentFS["about.md"] = &fstest.MapFile{Data: []byte("```json\n{\"title\":\"About\",\"toc\":true}\n```\n## Intro\n")}
+	generator, memStor = newTestGenerator(t, &Config{Author: "Andreas Linz"}, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	index, err = memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.NotContains(t, string(index), `<nav class="toc">`)
+	about, err = memStor.memFS.ReadFile("about.html")
+	require.NoError(t, err)
+	require.Contains(t, string(about), `<nav class="toc">`)
+	require.Contains(t, string(about), `<li><a href="#intro">Intro</a></li>`)
+}
+
+func TestGeneratorSummaries(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"blog/excerpt.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Excerpt\",\"created_at\":\"2023-01-02\"}\n```\nThe introduction.\n\n<!--more-->\n\nThe rest of the page.\n"),
+		},
+		"blog/described.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Described\",\"created_at\":\"2023-01-01\",\"description\":\"Hand-written.\"}\n```\nSome text.\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	page, err := memStor.memFS.ReadFile("blog/excerpt.html")
+	require.NoError(t, err)
+	require.Contains(t, string(page), `<meta name="description" content="The introduction.">`)
+	require.Contains(t, string(page), `7 words, 1 min read`)
+	list, err := memStor.memFS.ReadFile("blog/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(list), `<p>The introduction.</p>`)
+	require.Contains(t, string(list), `<p>Hand-written.</p>`)
+	feed, err := memStor.memFS.ReadFile("blog/feed.rss")
+	require.NoError(t, err)
+	require.Contains(t, string(feed), `<description>The introduction.</description>`)
+	require.Contains(t, string(feed), `<description>Hand-written.</description>`)
+}
+
+func TestGeneratorNavigation(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Home\"}\n```\nWelcome!\n")},
+		"blog/old.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Old\",\"created_at\":\"2023-01-01\"}\n```\nOld.\n"),
+		},
+		"blog/middle.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Middle\",\"created_at\":\"2023-01-02\"}\n```\nMiddle.\n"),
+		},
+		"blog/new.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"New\",\"created_at\":\"2023-01-03\"}\n```\nNew.\n"),
+		},
+		"docs/index.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Docs\"}\n```\nDocs.\n")},
+		"docs/guide/setup.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Setup\",\"created_at\":\"2023-01-01\"}\n```\nSetup.\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	middle, err := memStor.memFS.ReadFile("blog/middle.html")
+	require.NoError(t, err)
+	require.Contains(t, string(middle), `<a href='https://klingt.net/blog/new.html' rel='prev'>&larr; New</a>`)
+	require.Contains(t, string(middle), `<a href='https://klingt.net/blog/old.html' rel='next'>Old &rarr;</a>`)
+	require.Contains(t, string(middle), `<a href='https://klingt.net/index.html'>Home</a> / <a href='https://klingt.net/blog'>Blog</a>`)
+	newest, err := memStor.memFS.ReadFile("blog/new.html")
+	require.NoError(t, err)
+	require.NotContains(t, string(newest), `rel='prev'`)
+	require.Contains(t, string(newest), `<a href='https://klingt.net/blog/middle.html' rel='next'>Middle &rarr;</a>`)
+	oldest, err := memStor.memFS.ReadFile("blog/old.html")
+	require.NoError(t, err)
+	require.Contains(t, string(oldest), `<a href='https://klingt.net/blog/middle.html' rel='prev'>&larr; Middle</a>`)
+	require.NotContains(t, string(oldest), `rel='next'`)
+
+	setup, err := memStor.memFS.ReadFile("docs/guide/setup.html")
+	require.NoError(t, err)
+	require.Contains(t, string(setup), `<a href='https://klingt.net/docs/index.html'>Docs</a> / <a href='https://klingt.net/docs/guide'>Guide</a>`)
+	require.NotContains(t, string(setup), `rel='next'`)
+
+	docs, err := memStor.memFS.ReadFile("docs/index.html")
+	require.NoError(t, err)
+	require.NotContains(t, string(docs), `class="breadcrumbs"`)
+	index, err := memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.NotContains(t, string(index), `class="breadcrumbs"`)
+}
+
+func TestGeneratorMenu(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Home\"}\n```\nWelcome!\n")},
+		"about.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"About\",\"menu_title\":\"About me\"}\n```\nMe.\n")},
+		"docs/index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Docs\",\"menu_title\":\"Documentation\",\"menu_weight\":-1}\n```\nDocs.\n"),
+		},
+		"docs/setup.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Setup\"}\n```\nSetup.\n")},
+	}
+	config := &Config{
+		Author:  "Andreas Linz",
+		BaseURL: "https://klingt.net",
+		Menu: &MenuConfig{
+			Depth: 2,
+			Links: []MenuLinkConfig{{Title: "GitHub", URL: "https://github.com/klingtnet", Weight: 1}},
+		},
+	}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	setup, err := memStor.memFS.ReadFile("docs/setup.html")
+	require.NoError(t, err)
+	html := string(setup)
+	docs := strings.Index(html, ">Documentation</a>")
+	home := strings.Index(html, ">Home</a>")
+	about := strings.Index(html, ">About me</a>")
+	github := strings.Index(html, "<a href='https://github.com/klingtnet'>GitHub</a>")
+	require.True(t, docs >= 0 && docs < home && home < about && about < github, html)
+	require.Contains(t, html, "<li class=\"active\">\n    <a href='https://klingt.net/docs/index.html'>Documentation</a>")
+	require.Contains(t, html, "<li class=\"active\">\n    <a href='https://klingt.net/docs/setup.html'>Setup</a>")
+
+	index, err := memStor.memFS.ReadFile("index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(index), "<li class=\"active\">\n    <a href='https://klingt.net/index.html'>Home</a>")
+	require.NotContains(t, string(index), "<li class=\"active\">\n    <a href='https://klingt.net/docs'>")
+}
+
+func TestGeneratorDirMeta(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Home\"}\n```\nWelcome!\n")},
+		"blog/_dir.json": &fstest.MapFile{
+			Data: []byte(`{"title": "Articles", "description": "Thoughts on Go.", "defaults": {"tags": ["go"]}}`),
+		},
+		"blog/post.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Post\",\"created_at\":\"2023-01-01\"}\n```\nA post.\n"),
+		},
+		"docs/_dir.yaml": &fstest.MapFile{Data: []byte("sort:\n  by: title\n")},
+		"docs/beta.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Beta\",\"created_at\":\"2023-01-02\"}\n```\nBeta.\n"),
+		},
+		"docs/alpha.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Alpha\",\"created_at\":\"2023-01-01\"}\n```\nAlpha.\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	docs, err := memStor.memFS.ReadFile("docs/index.html")
+	require.NoError(t, err)
+	require.Less(t, strings.Index(string(docs), "Alpha"), strings.Index(string(docs), "Beta"), "pages are sorted by title")
+	alpha, err := memStor.memFS.ReadFile("docs/alpha.html")
+	require.NoError(t, err)
+	require.Contains(t, string(alpha), `<a href='https://klingt.net/docs/beta.html' rel='next'>Beta &rarr;</a>`)
+
+	require.NotContains(t, memStor.memFS, "blog/_dir.json")
+	list, err := memStor.memFS.ReadFile("blog/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(list), "<title>Articles</title>")
+	require.Contains(t, string(list), `<meta name="description" content="Thoughts on Go.">`)
+	require.Contains(t, string(list), "<a href='https://klingt.net/blog'>Articles</a>")
+	post, err := memStor.memFS.ReadFile("blog/post.html")
+	require.NoError(t, err)
+	require.Contains(t, string(post), "<a href='https://klingt.net/index.html'>Home</a> / <a href='https://klingt.net/blog'>Articles</a>")
+	require.Contains(t, memStor.memFS, "tags/go/index.html")
+
+	// Feeds contain the newest pages regardless of the sort order, and paginations of sorted lists are not labeled by date.
+	config.PageSize, config.FeedSize = 1, 1
+	generator, memStor = newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+	feed, err := memStor.memFS.ReadFile("docs/feed.rss")
+	require.NoError(t, err)
+	require.Contains(t, string(feed), "https://klingt.net/docs/beta.html")
+	require.NotContains(t, string(feed), "https://klingt.net/docs/alpha.html")
+	docs, err = memStor.memFS.ReadFile("docs/index.html")
+	require.NoError(t, err)
+	require.Contains(t, string(docs), "<a href='https://klingt.net/docs/page/2'>Next</a>")
+	require.NotContains(t, string(docs), ">Older</a>")
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+)
+
+// unresolvedLink fails the build if strict links are enabled, otherwise the error is recorded as warning of the page.
+func (g *Generator) unresolvedLink(page string, err error) error {
+	if g.config.StrictLinks {
+		return err
+	}
+
+	g.warn(page, err.Error())
+
+	return nil
+}
+
+// warn records a warning of the given page.
+func (g *Generator) warn(page, warning string) {
+	g.warningsMu.Lock()
+	defer g.warningsMu.Unlock()
+
+	if g.warnings[page] == nil {
+		g.warnings[page] = make(map[string]struct{})
+	}
+	g.warnings[page][warning] = struct{}{}
+}
+
+// replayWarnings records the warnings of the previous build for a page that is not generated again.
+func (g *Generator) replayWarnings(page *model.Page) {
+	path := filepath.ToSlash(page.Path())
+	for _, warning := range g.manifest.Warnings[path] {
+		g.warn(path, warning)
+	}
+}
+
+// pageWarnings returns the sorted warnings of the running build by page.
+func (g *Generator) pageWarnings() map[string][]string {
+	g.warningsMu.Lock()
+	defer g.warningsMu.Unlock()
+
+	pages := make(map[string][]string, len(g.warnings))
+	for page, set := range g.warnings {
+		for warning := range set {
+			pages[page] = append(pages[page], warning)
+		}
+		sort.Strings(pages[page])
+	}
+
+	return pages
+}
+
+// Warnings returns the sorted warnings of the last build, e.g. unresolved links.
+// Warnings of pages that were not generated again by an incremental build are reported as well.
+func (g *Generator) Warnings() []string {
+	var warnings []string
+	for _, pageWarnings := range g.pageWarnings() {
+		warnings = append(warnings, pageWarnings...)
+	}
+	sort.Strings(warnings)
+
+	return warnings
+}
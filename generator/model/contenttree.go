@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"io/fs"
 	"path"
@@ -25,9 +26,12 @@ type ContentTree struct {
 	fullPath string
 	name     string
 	children []Tree
+	meta     DirMeta
 }
 
-func readPage(ctx context.Context, contentFS fs.FS, name string) (*Page, error) {
+// readPage reads the page of the given name.
+// The defaults are applied to the front-matter before reading it, such that the page's values take precedence.
+func readPage(ctx context.Context, contentFS fs.FS, name string, defaults map[string]interface{}) (*Page, error) {
 	f, err := contentFS.Open(name)
 	if err != nil {
 		return nil, err
@@ -37,10 +41,18 @@ func readPage(ctx context.Context, contentFS fs.FS, name string) (*Page, error)
 	page := &Page{
 		name: name,
 	}
+	err = applyDefaults(defaults, &page.fm)
+	if err != nil {
+		return nil, fmt.Errorf("page %q: %w", name, err)
+	}
 	err = frontmatter.Read(ctx, f, &page.fm)
 	if err != nil {
 		return nil, err
 	}
+	err = page.fm.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("page %q: %w", name, err)
+	}
 	content, err := io.ReadAll(f)
 	if err != nil {
 		return nil, err
@@ -50,10 +62,13 @@ func readPage(ctx context.Context, contentFS fs.FS, name string) (*Page, error)
 	return page, nil
 }
 
+// newWithParent reads the content tree of the given directory.
+// Defaults are the front-matter defaults of the parent directories, see DirMeta.
 func newWithParent(
 	ctx context.Context,
 	contentFS fs.FS,
 	dir, parentDir string,
+	defaults map[string]interface{},
 ) (*ContentTree, error) {
 	tree := &ContentTree{
 		fullPath: filepath.Join(parentDir, dir),
@@ -64,6 +79,12 @@ func newWithParent(
 	if err != nil {
 		return nil, err
 	}
+	tree.meta, err = readDirMeta(contentFS, tree.fullPath, entries)
+	if err != nil {
+		return nil, err
+	}
+	defaults = cascade(defaults, tree.meta.Defaults)
+
 	for _, entry := range entries {
 		if entry.IsDir() {
 			subFS, err := fs.Sub(contentFS, entry.Name())
@@ -72,7 +93,7 @@ func newWithParent(
 			}
 
 			// 🌲 Recurse into subtree.
-			subTree, err := newWithParent(ctx, subFS, entry.Name(), tree.fullPath)
+			subTree, err := newWithParent(ctx, subFS, entry.Name(), tree.fullPath, defaults)
 			if err != nil {
 				return nil, err
 			}
@@ -80,10 +101,13 @@ func newWithParent(
 
 			continue
 		}
+		if isDirMeta(entry.Name()) {
+			continue
+		}
 		fullPath := path.Join(tree.fullPath, entry.Name())
 
 		if path.Ext(entry.Name()) == ".md" {
-			page, err := readPage(ctx, contentFS, entry.Name())
+			page, err := readPage(ctx, contentFS, entry.Name(), defaults)
 			if err != nil {
 				return nil, err
 			}
@@ -102,8 +126,10 @@ func newWithParent(
 	return tree, nil
 }
 
+// NewContentTree reads the content tree of the given directory.
+// The defaults of directory metadata files cascade to the pages of their directory and its subdirectories, see DirMeta.
 func NewContentTree(ctx context.Context, contentFS fs.FS, dir string) (*ContentTree, error) {
-	return newWithParent(ctx, contentFS, dir, "")
+	return newWithParent(ctx, contentFS, dir, "", nil)
 }
 
 func (content *ContentTree) Children() []Tree {
@@ -118,6 +144,46 @@ func (content *ContentTree) Name() string {
 	return content.name
 }
 
+// Meta returns the metadata of the directory, see DirMeta.
+func (content *ContentTree) Meta() DirMeta {
+	return content.meta
+}
+
+// IndexPage returns the index.md page of the directory, or nil if there is none.
+func (content *ContentTree) IndexPage() *Page {
+	for _, child := range content.children {
+		if page, ok := child.(*Page); ok && path.Base(page.Path()) == "index.md" {
+			return page
+		}
+	}
+
+	return nil
+}
+
+// Filter returns a copy of the tree that only contains the pages for which keep returns true.
+// Directories and other files are always retained.
+func (content *ContentTree) Filter(keep func(page *Page) bool) *ContentTree {
+	filtered := &ContentTree{
+		fullPath: content.fullPath,
+		name:     content.name,
+		meta:     content.meta,
+	}
+	for _, child := range content.children {
+		switch el := child.(type) {
+		case *ContentTree:
+			filtered.children = append(filtered.children, el.Filter(keep))
+		case *Page:
+			if keep(el) {
+				filtered.children = append(filtered.children, el)
+			}
+		default:
+			filtered.children = append(filtered.children, el)
+		}
+	}
+
+	return filtered
+}
+
 func (content *ContentTree) Walk(fn func(tree Tree) error) error {
 	err := fn(content)
 	if err != nil {
@@ -133,3 +199,33 @@ func (content *ContentTree) Walk(fn func(tree Tree) error) error {
 
 	return nil
 }
+
+// Pages returns all pages of the given tree in walk order.
+func Pages(tree Tree) []*Page {
+	var pages []*Page
+	// Walking the tree never fails since the callback does not return errors.
+	_ = tree.Walk(func(tree Tree) error {
+		if page, ok := tree.(*Page); ok {
+			pages = append(pages, page)
+		}
+
+		return nil
+	})
+
+	return pages
+}
+
+// Files returns all files of the given tree that are not pages, in walk order.
+func Files(tree Tree) []*File {
+	var files []*File
+	// Walking the tree never fails since the callback does not return errors.
+	_ = tree.Walk(func(tree Tree) error {
+		if file, ok := tree.(*File); ok {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+
+	return files
+}
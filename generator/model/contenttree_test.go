@@ -44,3 +44,49 @@ func TestContentTree(t *testing.T) {
 		"files",
 	}, dirs)
 }
+
+func TestPagesAndFiles(t *testing.T) {
+	content, err := NewContentTree(context.Background(), testutils.NewTestContentFS(t), ".")
+	require.NoError(t, err)
+
+	var pages []string
+	for _, page := range Pages(content) {
+		pages = append(pages, page.Path())
+	}
+	require.ElementsMatch(t, []string{"about.md", "index.md", "blog/first.md", "blog/second.md"}, pages)
+	var files []string
+	for _, file := range Files(content) {
+		files = append(files, file.Path())
+	}
+	require.Equal(t, []string{"files/random.txt"}, files)
+	require.Empty(t, Pages(content.Children()[2]))
+}
+
+func TestContentTreeFilter(t *testing.T) {
+	content, err := NewContentTree(context.Background(), testutils.NewTestContentFS(t), ".")
+	require.NoError(t, err)
+
+	filtered := content.Filter(func(page *Page) bool {
+		return page.Path() != "blog/first.md"
+	})
+
+	var paths []string
+	err = filtered.Walk(func(tree Tree) error {
+		paths = append(paths, tree.Path())
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		".",
+		"about.md",
+		"index.md",
+		"blog",
+		"blog/second.md",
+		"files",
+		"files/random.txt",
+	}, paths)
+
+	// The original tree is unchanged.
+	require.Len(t, content.Children()[1].Children(), 2)
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+	"github.com/klingtnet/static-site-generator/internal"
+)
+
+var (
+	// ErrAmbiguousDirMeta indicates a directory with more than one metadata file.
+	ErrAmbiguousDirMeta = fmt.Errorf("directory has more than one metadata file")
+	// ErrPageSpecificDefault indicates directory defaults that set values which must be unique to a page.
+	ErrPageSpecificDefault = fmt.Errorf("title, slug, url and aliases can not be set as defaults")
+	// ErrBadSort indicates a sort order that does not refer to a front-matter field or is neither asc nor desc.
+	ErrBadSort = fmt.Errorf("sort must refer to a front-matter field and its order must be asc or desc")
+)
+
+// dirMetaName is the name of directory metadata files without extension, e.g. _dir.json.
+const dirMetaName = "_dir"
+
+// dirMetaFormats are the supported formats of directory metadata files, which are also their file extensions.
+var dirMetaFormats = []string{"json", "yaml", "yml", "toml"}
+
+// isDirMeta returns true if the given filename is a directory metadata file.
+func isDirMeta(name string) bool {
+	ext := path.Ext(name)
+	if strings.TrimSuffix(name, ext) != dirMetaName {
+		return false
+	}
+	for _, format := range dirMetaFormats {
+		if ext == "."+format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DirMeta is the metadata of a content directory, read from a _dir.json, _dir.yaml or _dir.toml file.
+type DirMeta struct {
+	// Title of the directory, defaults to its title-cased name.
+	Title string `json:"title"`
+	// Description of the directory, e.g. used by its list page.
+	Description string `json:"description"`
+	// Weight determines the position of the directory's menu entry, entries with lower weights come first.
+	Weight int `json:"weight"`
+	// Sort determines the order of the directory's pages in its list page, feed and the navigation between them.
+	// Pages are sorted by creation date, newest first, if unset.
+	Sort Sort `json:"sort"`
+	// Defaults are front-matter values of all pages in the directory and its subdirectories.
+	// Values of subdirectories and the front-matter of pages take precedence.
+	Defaults map[string]interface{} `json:"defaults"`
+}
+
+// Sort orders pages by a front-matter field.
+type Sort struct {
+	// By is the name of the front-matter field, e.g. "title".
+	By string `json:"by"`
+	// Order is either "asc", the default, or "desc".
+	Order string `json:"order"`
+}
+
+// validate returns an error if the sort field is not a front-matter field, or if the order is unknown.
+func (s Sort) validate() error {
+	if s.By == "" && s.Order == "" {
+		return nil
+	}
+	if s.Order != "" && s.Order != "asc" && s.Order != "desc" {
+		return fmt.Errorf("order %q: %w", s.Order, ErrBadSort)
+	}
+
+	t := reflect.TypeOf(FrontMatter{})
+	for idx := 0; idx < t.NumField(); idx++ {
+		name, _, _ := strings.Cut(t.Field(idx).Tag.Get("json"), ",")
+		if name != "" && name == s.By {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("field %q: %w", s.By, ErrBadSort)
+}
+
+// readDirMeta reads the metadata file of the directory, if any.
+func readDirMeta(contentFS fs.FS, dir string, entries []fs.DirEntry) (DirMeta, error) {
+	var meta DirMeta
+	var found string
+	for _, entry := range entries {
+		if entry.IsDir() || !isDirMeta(entry.Name()) {
+			continue
+		}
+		if found != "" {
+			return meta, fmt.Errorf("directory %q: %w: %s and %s", dir, ErrAmbiguousDirMeta, found, entry.Name())
+		}
+		found = entry.Name()
+	}
+	if found == "" {
+		return meta, nil
+	}
+
+	data, err := fs.ReadFile(contentFS, found)
+	if err != nil {
+		return meta, err
+	}
+	err = frontmatter.Decode(strings.TrimPrefix(path.Ext(found), "."), data, &meta)
+	if err != nil {
+		return meta, fmt.Errorf("%s: %w", path.Join(dir, found), err)
+	}
+
+	err = meta.Sort.validate()
+	if err != nil {
+		return meta, fmt.Errorf("%s: %w", path.Join(dir, found), err)
+	}
+
+	var fm FrontMatter
+	err = applyDefaults(meta.Defaults, &fm)
+	if err != nil {
+		return meta, fmt.Errorf("%s: %w", path.Join(dir, found), err)
+	}
+	if fm.Title != "" || fm.Slug != "" || fm.URL != "" || len(fm.Aliases) > 0 {
+		return meta, fmt.Errorf("%s: %w", path.Join(dir, found), ErrPageSpecificDefault)
+	}
+
+	return meta, nil
+}
+
+// cascade returns the defaults of a directory, i.e. the defaults of its parent overridden by its own defaults.
+func cascade(parent, defaults map[string]interface{}) map[string]interface{} {
+	if len(defaults) == 0 {
+		return parent
+	}
+
+	merged := make(map[string]interface{}, len(parent)+len(defaults))
+	for key, value := range parent {
+		merged[key] = value
+	}
+	for key, value := range defaults {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// applyDefaults sets the given default values in the front-matter.
+func applyDefaults(defaults map[string]interface{}, fm *FrontMatter) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, fm)
+	if err != nil {
+		return fmt.Errorf("bad defaults: %w", err)
+	}
+
+	return nil
+}
+
+// Title returns the display title of the given tree.
+// This is the title of a directory's metadata if set, and the title-cased name of the tree otherwise.
+func Title(tree Tree) string {
+	if dir, ok := tree.(*ContentTree); ok && dir.meta.Title != "" {
+		return dir.meta.Title
+	}
+
+	return internal.TitleCase(tree.Name())
+}
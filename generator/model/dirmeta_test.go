@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirMeta(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"_dir.json": &fstest.MapFile{
+			Data: []byte(`{"title": "Start", "defaults": {"author": "John Doe", "tags": ["misc"]}}`),
+		},
+		"about.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"About\"}\n```\n")},
+		"blog/_dir.yaml": &fstest.MapFile{
+			Data: []byte("title: Articles\ndescription: Thoughts on Go.\nweight: 2\nsort:\n  by: title\n  order: desc\ndefaults:\n  tags: [go]\n  created_at: 2023-01-01\n"),
+		},
+		"blog/first.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"First\"}\n```\n")},
+		"blog/second.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Second\",\"author\":\"Jane Doe\",\"tags\":[\"testing\"]}\n```\n"),
+		},
+		"blog/notes/note.md": &fstest.MapFile{Data: []byte("---\ntitle: Note\ncreated_at: 2023-02-01\n---\n")},
+	}
+	content, err := NewContentTree(context.Background(), contentFS, ".")
+	require.NoError(t, err)
+
+	pages := make(map[string]FrontMatter)
+	dirs := make(map[string]DirMeta)
+	err = content.Walk(func(tree Tree) error {
+		switch el := tree.(type) {
+		case *Page:
+			pages[el.Path()] = *el.Frontmatter()
+		case *ContentTree:
+			dirs[el.Path()] = el.Meta()
+		case *File:
+			t.Errorf("unexpected file %s", el.Path())
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "Start", Title(content))
+	require.Equal(t, DirMeta{
+		Title:       "Articles",
+		Description: "Thoughts on Go.",
+		Weight:      2,
+		Sort:        Sort{By: "title", Order: "desc"},
+		Defaults:    map[string]interface{}{"tags": []interface{}{"go"}, "created_at": "2023-01-01T00:00:00Z"},
+	}, dirs["blog"])
+	require.Equal(t, DirMeta{}, dirs["blog/notes"], "only defaults cascade to subdirectories")
+
+	require.Equal(t, FrontMatter{Title: "About", Author: "John Doe", Tags: []string{"misc"}}, pages["about.md"])
+	require.Equal(t, FrontMatter{
+		Title:     "First",
+		Author:    "John Doe",
+		Tags:      []string{"go"},
+		CreatedAt: frontmatter.NewSimpleDate(2023, 1, 1),
+	}, pages["blog/first.md"])
+	require.Equal(t, FrontMatter{
+		Title:     "Second",
+		Author:    "Jane Doe",
+		Tags:      []string{"testing"},
+		CreatedAt: frontmatter.NewSimpleDate(2023, 1, 1),
+	}, pages["blog/second.md"])
+	require.Equal(t, FrontMatter{
+		Title:     "Note",
+		Author:    "John Doe",
+		Tags:      []string{"go"},
+		CreatedAt: frontmatter.NewSimpleDate(2023, 2, 1),
+	}, pages["blog/notes/note.md"])
+}
+
+func TestDirMetaErrors(t *testing.T) {
+	tCases := []struct {
+		name  string
+		files fstest.MapFS
+		err   error
+	}{
+		{
+			name: "ambiguous",
+			files: fstest.MapFS{
+				"_dir.json": &fstest.MapFile{Data: []byte(`{}`)},
+				"_dir.yml":  &fstest.MapFile{Data: []byte(`title: Home`)},
+			},
+			err: ErrAmbiguousDirMeta,
+		},
+		{
+			name:  "page specific default",
+			files: fstest.MapFS{"blog/_dir.json": &fstest.MapFile{Data: []byte(`{"defaults": {"slug": "post"}}`)}},
+			err:   ErrPageSpecificDefault,
+		},
+		{
+			name:  "unknown sort field",
+			files: fstest.MapFS{"_dir.json": &fstest.MapFile{Data: []byte(`{"sort": {"by": "weight"}}`)}},
+			err:   ErrBadSort,
+		},
+		{
+			name:  "unknown sort order",
+			files: fstest.MapFS{"_dir.json": &fstest.MapFile{Data: []byte(`{"sort": {"by": "title", "order": "up"}}`)}},
+			err:   ErrBadSort,
+		},
+		{
+			name:  "bad format",
+			files: fstest.MapFS{"_dir.toml": &fstest.MapFile{Data: []byte(`title = `)}},
+			err:   frontmatter.ErrBadFrontMatter,
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			_, err := NewContentTree(context.Background(), tCase.files, ".")
+			require.ErrorIs(t, err, tCase.err)
+		})
+	}
+}
@@ -1,9 +1,9 @@
 package model
 
 import (
+	"path"
 	"sort"
-
-	"github.com/klingtnet/static-site-generator/internal"
+	"strings"
 )
 
 // MenuEntry is an entry in the navigation menu.
@@ -12,15 +12,54 @@ type MenuEntry struct {
 	Title string
 	// Path of the page file.
 	Path string
+	// Link is the output path of the entry, relative to the output directory.
+	// For external entries, Link is the URL of the entry.
+	// Directories link to their index page or, if they contain pages, their list page.
+	// Link is empty for directories that have neither, i.e. that only contain subdirectories.
+	Link string
 	// IsDir is true if path is pointing to a directory.
 	IsDir bool
+	// External is true if the entry does not refer to a page of the website, see MenuOptions.
+	External bool
+	// Weight determines the order of entries, entries with lower weights come first.
+	Weight int
+	// Active is true if the entry refers to the rendered page or a directory containing it, see ActiveMenu.
+	Active bool
+	// Children are the entries of a directory, if the menu includes its level.
+	Children []MenuEntry
+}
+
+// MenuOptions configure the menu, see Menu.
+type MenuOptions struct {
+	// Depth is the number of directory levels in the menu, where 1 only includes the root level.
+	// Values below 1 are treated as 1.
+	Depth int
+	// Links are additional entries of the root level, e.g. links to external websites.
+	Links []MenuEntry
 }
 
 // Menu builds a slice of menu entries for the given content tree.
+// The output path of pages, used as link, is determined by pagePath.
 //
-// Note that the function will not recurse into the tree, instead
-// the menu will only contain entries for the root level.
-func Menu(tree Tree) []MenuEntry {
+// The menu contains nested entries for the directories up to the depth of the given options.
+// Entries are ordered by their weight, followed by the home page, directories and pages which are sorted by title.
+// The menu_title and menu_weight of a page's front-matter override its title and weight,
+// the index.md of a directory overrides the title and weight of the directory's metadata.
+func Menu(tree Tree, pagePath func(*Page) string, options MenuOptions) []MenuEntry {
+	depth := options.Depth
+	if depth < 1 {
+		depth = 1
+	}
+
+	menu := append(menuEntries(tree, pagePath, depth, false), options.Links...)
+	sortMenu(menu)
+
+	return menu
+}
+
+// menuEntries returns the menu entries of the given tree and its subdirectories up to the given depth.
+// Index pages of nested directories are represented by the entry of their directory.
+func menuEntries(tree Tree, pagePath func(*Page) string, depth int, nested bool) []MenuEntry {
 	menu := []MenuEntry{}
 	containsPages := func(tree Tree) bool {
 		for _, child := range tree.Children() {
@@ -36,36 +75,78 @@ func Menu(tree Tree) []MenuEntry {
 	for _, child := range tree.Children() {
 		switch el := child.(type) {
 		case *ContentTree:
+			entry := MenuEntry{Title: Title(el), Path: el.Path(), IsDir: true, Weight: el.meta.Weight}
 			if containsPages(el) {
-				menu = append(menu, MenuEntry{Title: internal.TitleCase(el.Name()), Path: el.Path(), IsDir: true})
+				entry.Link = el.Path()
+			}
+			if index := el.IndexPage(); index != nil {
+				entry.Link = pagePath(index)
+				if index.fm.MenuTitle != "" {
+					entry.Title = index.fm.MenuTitle
+				}
+				if index.fm.MenuWeight != 0 {
+					entry.Weight = index.fm.MenuWeight
+				}
+			}
+			if depth > 1 {
+				entry.Children = menuEntries(el, pagePath, depth-1, true)
+				sortMenu(entry.Children)
+			}
+			if containsPages(el) || len(entry.Children) > 0 {
+				menu = append(menu, entry)
 			}
 		case *Page:
-			if el.fm.Hidden {
+			isIndex := path.Base(el.Path()) == "index.md"
+			if el.fm.Hidden || (nested && isIndex) {
 				continue
 			}
 
-			if el.Path() == "index.md" {
-				menu = append(menu, MenuEntry{Title: "Home", Path: el.Path()})
-			} else {
-				menu = append(menu, MenuEntry{Title: el.Name(), Path: el.Path()})
+			entry := MenuEntry{Title: el.Name(), Path: el.Path(), Link: pagePath(el), Weight: el.fm.MenuWeight}
+			switch {
+			case el.fm.MenuTitle != "":
+				entry.Title = el.fm.MenuTitle
+			case el.Path() == "index.md":
+				entry.Title = "Home"
 			}
+			menu = append(menu, entry)
 		}
 	}
 
-	// Sort directories before pages.
-	sort.Slice(menu, func(i, j int) bool {
-		a, b := menu[i], menu[j]
+	return menu
+}
 
-		if a.Path == "index.md" {
-			return true
-		}
+// sortMenu sorts entries by weight, followed by the home page, directories and pages, which are sorted by title.
+func sortMenu(menu []MenuEntry) {
+	sort.SliceStable(menu, func(i, j int) bool {
+		a, b := menu[i], menu[j]
 
-		if a.IsDir == b.IsDir {
+		switch {
+		case a.Weight != b.Weight:
+			return a.Weight < b.Weight
+		case a.Path == "index.md" || b.Path == "index.md":
+			return a.Path == "index.md"
+		case a.IsDir != b.IsDir:
+			// Sort directories before pages.
+			return a.IsDir
+		default:
 			return a.Title < b.Title
 		}
-
-		return a.IsDir
 	})
+}
 
-	return menu
+// ActiveMenu returns a copy of the menu where the entries leading to the given path are marked as active.
+// Page entries are active if their path equals the given path, directory entries if the path is inside the directory.
+func ActiveMenu(menu []MenuEntry, path string) []MenuEntry {
+	if len(menu) == 0 {
+		return nil
+	}
+
+	active := make([]MenuEntry, len(menu))
+	for idx, entry := range menu {
+		entry.Active = !entry.External && (entry.Path == path || (entry.IsDir && strings.HasPrefix(path, entry.Path+"/")))
+		entry.Children = ActiveMenu(entry.Children, path)
+		active[idx] = entry
+	}
+
+	return active
 }
@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"testing"
+	"testing/fstest"
 
 	"github.com/klingtnet/static-site-generator/internal/testutils"
 	"github.com/stretchr/testify/require"
@@ -12,15 +13,19 @@ func TestMenu(t *testing.T) {
 	content, err := NewContentTree(context.Background(), testutils.NewTestContentFS(t), ".")
 	require.NoError(t, err)
 
+	pagePath := func(page *Page) string {
+		return "out/" + page.Path()
+	}
+
 	rootMenu := []MenuEntry{
-		{Title: "Home", Path: "index.md"},
-		{Title: "About", Path: "about.md"},
-		{Title: "Blog", Path: "blog", IsDir: true},
+		{Title: "Home", Path: "index.md", Link: "out/index.md"},
+		{Title: "About", Path: "about.md", Link: "out/about.md"},
+		{Title: "Blog", Path: "blog", Link: "blog", IsDir: true},
 	}
 	require.ElementsMatch(
 		t,
 		rootMenu,
-		Menu(content),
+		Menu(content, pagePath, MenuOptions{}),
 	)
 
 	var blog *ContentTree
@@ -37,9 +42,92 @@ func TestMenu(t *testing.T) {
 	require.ElementsMatch(
 		t,
 		[]MenuEntry{
-			{Title: "First Article", Path: "blog/first.md"},
-			{Title: "Second Article", Path: "blog/second.md"},
+			{Title: "First Article", Path: "blog/first.md", Link: "out/blog/first.md"},
+			{Title: "Second Article", Path: "blog/second.md", Link: "out/blog/second.md"},
 		},
-		Menu(blog),
+		Menu(blog, pagePath, MenuOptions{}),
 	)
 }
+
+func TestMenuOptions(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Welcome\"}\n```\n")},
+		"about.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"About\",\"menu_weight\":-1}\n```\n")},
+		"blog/index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Blog\",\"menu_title\":\"Articles\",\"menu_weight\":1}\n```\n"),
+		},
+		"blog/post.md":        &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Post\",\"menu_title\":\"A Post\"}\n```\n")},
+		"docs/guide/setup.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"Setup\"}\n```\n")},
+	}
+	content, err := NewContentTree(context.Background(), contentFS, ".")
+	require.NoError(t, err)
+	pagePath := func(page *Page) string {
+		return page.Path()
+	}
+
+	tCases := []struct {
+		name     string
+		options  MenuOptions
+		expected []MenuEntry
+	}{
+		{
+			name: "root level",
+			expected: []MenuEntry{
+				{Title: "About", Path: "about.md", Link: "about.md", Weight: -1},
+				{Title: "Home", Path: "index.md", Link: "index.md"},
+				{Title: "Articles", Path: "blog", Link: "blog/index.md", IsDir: true, Weight: 1},
+			},
+		},
+		{
+			name: "nested",
+			options: MenuOptions{
+				Depth: 3,
+				Links: []MenuEntry{{Title: "GitHub", Link: "https://github.com", External: true, Weight: 2}},
+			},
+			expected: []MenuEntry{
+				{Title: "About", Path: "about.md", Link: "about.md", Weight: -1},
+				{Title: "Home", Path: "index.md", Link: "index.md"},
+				{Title: "Docs", Path: "docs", IsDir: true, Children: []MenuEntry{
+					{Title: "Guide", Path: "docs/guide", Link: "docs/guide", IsDir: true, Children: []MenuEntry{
+						{Title: "Setup", Path: "docs/guide/setup.md", Link: "docs/guide/setup.md"},
+					}},
+				}},
+				{Title: "Articles", Path: "blog", Link: "blog/index.md", IsDir: true, Weight: 1, Children: []MenuEntry{
+					{Title: "A Post", Path: "blog/post.md", Link: "blog/post.md"},
+				}},
+				{Title: "GitHub", Link: "https://github.com", External: true, Weight: 2},
+			},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			require.Equal(t, tCase.expected, Menu(content, pagePath, tCase.options))
+		})
+	}
+}
+
+func TestActiveMenu(t *testing.T) {
+	menu := []MenuEntry{
+		{Title: "Home", Path: "index.md"},
+		{Title: "Docs", Path: "docs", IsDir: true, Children: []MenuEntry{
+			{Title: "Guide", Path: "docs/guide", IsDir: true, Children: []MenuEntry{
+				{Title: "Setup", Path: "docs/guide/setup.md"},
+			}},
+			{Title: "FAQ", Path: "docs/faq.md"},
+		}},
+		{Title: "GitHub", Link: "https://github.com", External: true},
+	}
+
+	active := ActiveMenu(menu, "docs/guide/setup.md")
+	require.False(t, active[0].Active)
+	require.True(t, active[1].Active)
+	require.True(t, active[1].Children[0].Active)
+	require.True(t, active[1].Children[0].Children[0].Active)
+	require.False(t, active[1].Children[1].Active)
+	require.False(t, active[2].Active)
+	require.False(t, menu[1].Active, "the given menu must not be modified")
+
+	active = ActiveMenu(menu, "index.md")
+	require.True(t, active[0].Active)
+	require.False(t, active[1].Active)
+}
@@ -1,6 +1,21 @@
 package model
 
-import "github.com/klingtnet/static-site-generator/frontmatter"
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+)
+
+var (
+	// ErrUnsafePath indicates a path that is not relative or leaves its directory.
+	ErrUnsafePath = fmt.Errorf("path must be relative and must not leave the output directory")
+	// ErrNotHTML indicates a slug or URL that would not store the page as HTML file.
+	ErrNotHTML = fmt.Errorf("slug must not end with a file extension and url must end with a slash or .html")
+)
 
 // FrontMatter stores metadata of a page.
 type FrontMatter struct {
@@ -12,10 +27,93 @@ type FrontMatter struct {
 	Description string `json:"description"`
 	// CreatedAt determines when the article was written.
 	CreatedAt *frontmatter.SimpleDate `json:"created_at"`
+	// UpdatedAt determines when the article was last changed, it is optional.
+	UpdatedAt *frontmatter.SimpleDate `json:"updated_at"`
 	// Tags are list of words categorizing the page.
 	Tags []string `json:"tags"`
 	// Hidden excludes page from navigation menu.
 	Hidden bool `json:"hidden"`
+	// Draft excludes the page from the build, unless drafts are enabled.
+	Draft bool `json:"draft"`
+	// PublishAt excludes the page from builds before the given date, unless future pages are enabled.
+	PublishAt *frontmatter.SimpleDate `json:"publish_at"`
+	// Slug overrides the filename of the page, which defaults to the slugified title.
+	// The slug must not contain a file extension or path separators.
+	Slug string `json:"slug"`
+	// URL overrides the output path of the page relative to the website's root, e.g. posts/hello.html.
+	// A trailing slash stores the page as index.html of the given directory.
+	URL string `json:"url"`
+	// Aliases are former URLs of the page, relative to the website's root, that redirect to the page.
+	// As for URL, a trailing slash refers to the index.html of the given directory.
+	Aliases []string `json:"aliases"`
+	// TOC enables or disables the table of contents of the page, which defaults to the site's configuration.
+	TOC *bool `json:"toc"`
+	// MenuTitle overrides the title of the page's menu entry.
+	// For index.md pages, it sets the title of their directory's menu entry.
+	MenuTitle string `json:"menu_title"`
+	// MenuWeight determines the position of the page's menu entry, entries with lower weights come first.
+	// For index.md pages, it sets the weight of their directory's menu entry.
+	MenuWeight int `json:"menu_weight"`
+}
+
+// isSafeURL returns true if the given URL path is relative to, and does not leave, the website's root.
+func isSafeURL(url string) bool {
+	return !strings.ContainsAny(url, `\:?#`) && filepath.IsLocal(strings.TrimPrefix(url, "/"))
+}
+
+// Validate returns an error if the slug, URL or aliases of the front-matter are unsafe,
+// i.e. if they would result in a file outside of the output directory,
+// or if the slug or URL would store the page as a file that is not HTML.
+func (fm *FrontMatter) Validate() error {
+	if fm.Slug != "" && (strings.ContainsAny(fm.Slug, `/\:?#`) || !filepath.IsLocal(fm.Slug)) {
+		return fmt.Errorf("slug %q: %w", fm.Slug, ErrUnsafePath)
+	}
+	// .html is appended to the slug, such that only file extensions are rejected
+	// and other dots are allowed, e.g. in version numbers like go-1.21-released.
+	switch strings.ToLower(filepath.Ext(fm.Slug)) {
+	case ".html", ".htm", ".xml", ".rss":
+		return fmt.Errorf("slug %q: %w", fm.Slug, ErrNotHTML)
+	}
+
+	if fm.URL != "" && !isSafeURL(fm.URL) {
+		return fmt.Errorf("url %q: %w", fm.URL, ErrUnsafePath)
+	}
+	if fm.URL != "" && !strings.HasSuffix(fm.URL, "/") && path.Ext(fm.URL) != ".html" {
+		return fmt.Errorf("url %q: %w", fm.URL, ErrNotHTML)
+	}
+
+	for _, alias := range fm.Aliases {
+		if !isSafeURL(alias) {
+			return fmt.Errorf("alias %q: %w", alias, ErrUnsafePath)
+		}
+	}
+
+	return nil
+}
+
+// ModifiedAt returns when the page was last changed, i.e. UpdatedAt if set and CreatedAt otherwise.
+func (fm *FrontMatter) ModifiedAt() *frontmatter.SimpleDate {
+	if fm.UpdatedAt != nil {
+		return fm.UpdatedAt
+	}
+
+	return fm.CreatedAt
+}
+
+// WordsPerMinute is the reading speed used to estimate the reading time of pages.
+const WordsPerMinute = 200
+
+// Summary contains metadata that is derived from the content of a page.
+type Summary struct {
+	// Excerpt is the plain text of the page up to the summary separator, or its first words.
+	Excerpt string
+	// WordCount is the number of words of the page's text.
+	WordCount int
+}
+
+// ReadingTime returns the estimated reading time in minutes, which is at least one minute for non-empty pages.
+func (s Summary) ReadingTime() int {
+	return (s.WordCount + WordsPerMinute - 1) / WordsPerMinute
 }
 
 type Page struct {
@@ -23,6 +121,10 @@ type Page struct {
 	fm       FrontMatter
 	fullPath string
 	name     string
+	// summarize derives the summary when it is first requested, see SetSummarizer.
+	summarize   func(*Page) Summary
+	summary     Summary
+	summaryOnce sync.Once
 }
 
 func (p *Page) Children() []Tree {
@@ -48,3 +150,21 @@ func (p *Page) Frontmatter() *FrontMatter {
 func (p *Page) Content() []byte {
 	return p.content
 }
+
+// Summary returns the summary of the page, which is empty unless a summarizer is set, see SetSummarizer.
+// The summary is derived only once, when it is first requested.
+func (p *Page) Summary() Summary {
+	p.summaryOnce.Do(func() {
+		if p.summarize != nil {
+			p.summary = p.summarize(p)
+		}
+	})
+
+	return p.summary
+}
+
+// SetSummarizer sets the function that derives the summary of the page.
+// It must be set before the summary is requested for the first time.
+func (p *Page) SetSummarizer(summarize func(*Page) Summary) {
+	p.summarize = summarize
+}
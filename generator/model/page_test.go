@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontMatterValidate(t *testing.T) {
+	tCases := []struct {
+		name string
+		fm   FrontMatter
+		err  error
+	}{
+		{"empty", FrontMatter{}, nil},
+		{"slug", FrontMatter{Slug: "hello-world"}, nil},
+		{"url", FrontMatter{URL: "/posts/hello.html"}, nil},
+		{"url directory", FrontMatter{URL: "posts/hello/"}, nil},
+		{"slug with separator", FrontMatter{Slug: "posts/hello"}, ErrUnsafePath},
+		{"slug parent", FrontMatter{Slug: ".."}, ErrUnsafePath},
+		{"url parent", FrontMatter{URL: "../hello.html"}, ErrUnsafePath},
+		{"url escaping", FrontMatter{URL: "posts/../../hello.html"}, ErrUnsafePath},
+		{"url root", FrontMatter{URL: "/"}, ErrUnsafePath},
+		{"slug with version number", FrontMatter{Slug: "go-1.21-released"}, nil},
+		{"slug with extension", FrontMatter{Slug: "hello.html"}, ErrNotHTML},
+		{"slug with other extension", FrontMatter{Slug: "feed.XML"}, ErrNotHTML},
+		{"url without extension", FrontMatter{URL: "/posts/hello"}, ErrNotHTML},
+		{"url with other extension", FrontMatter{URL: "/feed.xml"}, ErrNotHTML},
+		{"url with scheme", FrontMatter{URL: "https://example.com/hello.html"}, ErrUnsafePath},
+		{"aliases", FrontMatter{Aliases: []string{"/old.html", "posts/old/"}}, nil},
+		{"alias parent", FrontMatter{Aliases: []string{"/old.html", "../old.html"}}, ErrUnsafePath},
+	}
+
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			require.ErrorIs(t, tCase.fm.Validate(), tCase.err)
+		})
+	}
+}
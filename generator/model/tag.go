@@ -0,0 +1,92 @@
+package model
+
+import (
+	"path"
+	"sort"
+)
+
+// Tag groups all pages sharing the same tag.
+//
+// Tag implements Tree, hence it can be rendered like a content directory
+// where the tagged pages are the children of the tag.
+type Tag struct {
+	fullPath string
+	name     string
+	pages    []Tree
+}
+
+func (t *Tag) Children() []Tree {
+	return t.pages
+}
+
+func (t *Tag) Path() string {
+	return t.fullPath
+}
+
+func (t *Tag) Name() string {
+	return t.name
+}
+
+func (t *Tag) Walk(fn func(tree Tree) error) error {
+	err := fn(t)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range t.pages {
+		err = page.Walk(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of pages having this tag.
+func (t *Tag) Count() int {
+	return len(t.pages)
+}
+
+// Tags collects the tags of all visible pages in the given tree.
+//
+// Tags are identified by their slug, hence tags that only differ in casing or punctuation
+// are merged into a single one that is named after the first occurrence.
+// The path of a tag is its slug below dir.  The result is sorted by path.
+func Tags(tree Tree, dir string, slugify func(string) string) []*Tag {
+	bySlug := make(map[string]*Tag)
+	_ = tree.Walk(func(tree Tree) error {
+		page, ok := tree.(*Page)
+		if !ok || page.fm.Hidden {
+			return nil
+		}
+
+		seen := make(map[string]bool)
+		for _, name := range page.fm.Tags {
+			slug := slugify(name)
+			if slug == "" || seen[slug] {
+				continue
+			}
+			seen[slug] = true
+
+			tag, ok := bySlug[slug]
+			if !ok {
+				tag = &Tag{fullPath: path.Join(dir, slug), name: name}
+				bySlug[slug] = tag
+			}
+			tag.pages = append(tag.pages, page)
+		}
+
+		return nil
+	})
+
+	tags := make([]*Tag, 0, len(bySlug))
+	for _, tag := range bySlug {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].fullPath < tags[j].fullPath
+	})
+
+	return tags
+}
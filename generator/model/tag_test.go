@@ -0,0 +1,28 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/klingtnet/static-site-generator/internal/testutils"
+	"github.com/klingtnet/static-site-generator/slug"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	content, err := NewContentTree(context.Background(), testutils.NewTestContentFS(t), ".")
+	require.NoError(t, err)
+
+	tags := Tags(content, "tags", slug.NewSlugifier('-').Slugify)
+	require.Len(t, tags, 2)
+
+	// "Go" and "go" are merged into a single tag.
+	require.Equal(t, "Go", tags[0].Name())
+	require.Equal(t, "tags/go", tags[0].Path())
+	require.Equal(t, 2, tags[0].Count())
+
+	require.Equal(t, "testing", tags[1].Name())
+	require.Equal(t, "tags/testing", tags[1].Path())
+	require.Equal(t, 1, tags[1].Count())
+	require.Equal(t, "blog/first.md", tags[1].Children()[0].Path())
+}
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
+)
+
+// pageJob is a page to render together with its ancestor directories, starting with the root directory.
+type pageJob struct {
+	page *model.Page
+	dirs []*model.ContentTree
+	// siblings are the listed pages of the page's directory except for its index page, sorted newest first.
+	// They are shared by all pages of the directory.
+	siblings []renderer.TemplatePage
+	// position is the index of the page in siblings, or -1 if the page is not listed.
+	position int
+}
+
+// walkPages calls fn for every page of the given directory and its subdirectories.
+// The ancestors of dir are passed to fn as well, followed by the directories down to the page.
+// The siblings of the pages are determined once per directory.
+func walkPages(dir *model.ContentTree, ancestors []*model.ContentTree, fn func(job pageJob) error) error {
+	// Limit the capacity such that siblings do not share the backing array.
+	dirs := append(ancestors[:len(ancestors):len(ancestors)], dir)
+
+	var siblings []renderer.TemplatePage
+	positions := make(map[string]int)
+	for _, page := range renderer.ListPages(dir) {
+		if filepath.Base(page.Path) != "index.md" {
+			positions[page.Path] = len(siblings)
+			siblings = append(siblings, page)
+		}
+	}
+
+	for _, child := range dir.Children() {
+		var err error
+		switch el := child.(type) {
+		case *model.ContentTree:
+			err = walkPages(el, dirs, fn)
+		case *model.Page:
+			position, ok := positions[el.Path()]
+			if !ok {
+				position = -1
+			}
+			err = fn(pageJob{page: el, dirs: dirs, siblings: siblings, position: position})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// breadcrumb returns the title and list page link of the given directory.
+func (g *Generator) breadcrumb(dir *model.ContentTree) renderer.Breadcrumb {
+	crumb := renderer.Breadcrumb{Title: model.Title(dir)}
+	if dir.Path() == "." && dir.Meta().Title == "" {
+		crumb.Title = "Home"
+	}
+
+	if index := dir.IndexPage(); index != nil {
+		crumb.Link = g.pagePath(index)
+	} else if isListDir(dir) {
+		crumb.Link = dir.Path()
+	}
+
+	return crumb
+}
+
+// navigation returns the section, breadcrumbs and adjacent pages of the given page.
+func (g *Generator) navigation(job pageJob) renderer.Navigation {
+	var nav renderer.Navigation
+	isIndex := filepath.Base(job.page.Path()) == "index.md"
+
+	sections := job.dirs
+	if isIndex {
+		// The directory of an index page is the section of its pages, not the section of the index page itself.
+		sections = sections[:len(sections)-1]
+	}
+	if len(sections) > 1 {
+		for _, dir := range sections {
+			nav.Breadcrumbs = append(nav.Breadcrumbs, g.breadcrumb(dir))
+		}
+		section := nav.Breadcrumbs[len(nav.Breadcrumbs)-1]
+		nav.Section = &section
+	}
+
+	if job.position < 0 {
+		return nav
+	}
+	// Siblings are in the order of the list page.
+	if job.position > 0 {
+		nav.Prev = &job.siblings[job.position-1]
+	}
+	if job.position < len(job.siblings)-1 {
+		nav.Next = &job.siblings[job.position+1]
+	}
+
+	return nav
+}
+
+// adjacentPage is the part of an adjacent page that is linked by the navigation of a page, see navigationDigest.
+type adjacentPage struct {
+	Title string
+	Link  string
+}
+
+// navigationDigest returns the JSON encoding of the given navigation for use in a digest.
+// Only the title and link of adjacent pages is included, such that changes to their content do not affect the digest.
+func (g *Generator) navigationDigest(navigation renderer.Navigation) ([]byte, error) {
+	adjacent := func(page *renderer.TemplatePage) *adjacentPage {
+		if page == nil {
+			return nil
+		}
+
+		return &adjacentPage{Title: page.FM.Title, Link: g.outputPath(*page)}
+	}
+
+	return json.Marshal(struct {
+		Section     *renderer.Breadcrumb
+		Breadcrumbs []renderer.Breadcrumb
+		Prev, Next  *adjacentPage
+	}{
+		Section:     navigation.Section,
+		Breadcrumbs: navigation.Breadcrumbs,
+		Prev:        adjacent(navigation.Prev),
+		Next:        adjacent(navigation.Next),
+	})
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoBuild indicates that an operation requires a previous build.
+var ErrNoBuild = fmt.Errorf("website was not built yet")
+
+// outputSet is a set of output file names that is safe for concurrent use.
+type outputSet struct {
+	lock  sync.Mutex
+	names map[string]struct{}
+}
+
+func newOutputSet() *outputSet {
+	return &outputSet{names: make(map[string]struct{})}
+}
+
+// add records the given name, which is normalized to use slashes as separator.
+func (o *outputSet) add(name string) {
+	o.lock.Lock()
+	o.names[filepath.ToSlash(filepath.Clean(name))] = struct{}{}
+	o.lock.Unlock()
+}
+
+// list returns the sorted names of the set.
+func (o *outputSet) list() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	names := make([]string, 0, len(o.names))
+	for name := range o.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (o *outputSet) contains(name string) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	_, ok := o.names[name]
+	return ok
+}
+
+// isHidden returns true if any element of the slash separated name starts with a dot.
+// Hidden files, e.g. the build cache manifest or a .git folder, are never pruned.
+func isHidden(name string) bool {
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stale returns the sorted names of all files in storage that were not produced by the last build.
+// Note that hidden files and files in hidden directories are never considered stale.
+func (g *Generator) Stale(ctx context.Context) ([]string, error) {
+	if g.produced == nil {
+		return nil, ErrNoBuild
+	}
+
+	names, err := g.stor.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, name := range names {
+		if isHidden(name) || g.produced.contains(name) {
+			continue
+		}
+		stale = append(stale, name)
+	}
+	sort.Strings(stale)
+
+	return stale, nil
+}
+
+// prune removes stale files from storage.
+func (g *Generator) prune(ctx context.Context) error {
+	stale, err := g.Stale(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range stale {
+		err = g.stor.Remove(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
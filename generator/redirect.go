@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
+)
+
+// Supported redirect file formats, see Config.Redirects.
+const (
+	RedirectsNetlify = "netlify"
+	RedirectsNginx   = "nginx"
+)
+
+// Redirect is a permanent redirect from an alias of a page to the page itself.
+type Redirect struct {
+	// From is the URL path of the alias, e.g. /old/post.html or /old/post/.
+	From string
+	// To is the URL path of the page.
+	To string
+}
+
+// Paths returns all URL paths that are redirected, i.e. From and, if From refers to a directory, its index.html.
+func (r Redirect) Paths() []string {
+	if strings.HasSuffix(r.From, "/") {
+		return []string{r.From, r.From + "index.html"}
+	}
+
+	return []string{r.From}
+}
+
+// redirectFormat describes a file that configures redirects of a web server or hosting provider.
+type redirectFormat struct {
+	// name of the file inside the output directory.
+	name string
+	// write encodes the redirects.
+	write func(w io.Writer, redirects []Redirect) error
+}
+
+var redirectFormats = map[string]redirectFormat{
+	RedirectsNetlify: {
+		// https://docs.netlify.com/routing/redirects/
+		name: "_redirects",
+		write: func(w io.Writer, redirects []Redirect) error {
+			for _, redirect := range redirects {
+				// Forcing the redirect is required since the alias exists as redirect page.
+				_, err := fmt.Fprintf(w, "%s %s 301!\n", redirect.From, redirect.To)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	RedirectsNginx: {
+		// Entries of a map block, e.g. map $uri $redirect_uri { include redirects.map; }
+		name: "redirects.map",
+		write: func(w io.Writer, redirects []Redirect) error {
+			for _, redirect := range redirects {
+				for _, from := range redirect.Paths() {
+					_, err := fmt.Fprintf(w, "%s %s;\n", from, redirect.To)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		},
+	},
+}
+
+// Redirects returns the redirects of the last build, sorted by their alias.
+func (g *Generator) Redirects() []Redirect {
+	return g.redirects
+}
+
+// pageRedirects returns the redirects of all page aliases, sorted by their alias.
+func (g *Generator) pageRedirects(content *model.ContentTree) []Redirect {
+	var redirects []Redirect
+	for _, page := range model.Pages(content) {
+		to := "/" + filepath.ToSlash(g.pagePath(page))
+		for _, alias := range page.Frontmatter().Aliases {
+			redirects = append(redirects, Redirect{From: "/" + strings.TrimPrefix(alias, "/"), To: to})
+		}
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+
+	return redirects
+}
+
+// redirectPage returns a minimal HTML page that redirects to the given URL.
+func redirectPage(url string) []byte {
+	url = html.EscapeString(url)
+
+	return []byte(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=` + url + `">
+<link rel="canonical" href="` + url + `">
+<title>Redirecting to ` + url + `</title>
+</head>
+<body>
+<a href="` + url + `">Redirecting to ` + url + `</a>
+</body>
+</html>
+`)
+}
+
+// renderRedirects stores a redirect page for every alias as well as the configured redirect files.
+func (g *Generator) renderRedirects(ctx context.Context) error {
+	for _, redirect := range g.redirects {
+		err := g.storeBytes(
+			ctx,
+			renderer.URLPath(redirect.From),
+			redirectPage(renderer.AbsLink(g.config.BaseURL, redirect.To)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, format := range g.config.Redirects {
+		buf := new(bytes.Buffer)
+		err := redirectFormats[format].write(buf, g.redirects)
+		if err != nil {
+			return err
+		}
+		err = g.storeBytes(ctx, redirectFormats[format].name, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
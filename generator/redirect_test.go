@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorRedirects(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"index.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Home\"}\n```\n"),
+		},
+		"blog/post.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Post\",\"aliases\":[\"/2021/old-post.html\",\"articles/post/\"]}\n```\n"),
+		},
+	}
+	config := &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net", Redirects: []string{RedirectsNetlify, RedirectsNginx}}
+	generator, memStor := newTestGenerator(t, config, contentFS)
+	require.NoError(t, generator.Run(context.Background()))
+
+	require.Equal(t, []Redirect{
+		{From: "/2021/old-post.html", To: "/blog/post.html"},
+		{From: "/articles/post/", To: "/blog/post.html"},
+	}, generator.Redirects())
+
+	for _, name := range []string{"2021/old-post.html", "articles/post/index.html"} {
+		page, err := memStor.memFS.ReadFile(name)
+		require.NoError(t, err, name)
+		require.Contains(t, string(page), `<meta http-equiv="refresh" content="0; url=https://klingt.net/blog/post.html">`)
+		require.Contains(t, string(page), `<link rel="canonical" href="https://klingt.net/blog/post.html">`)
+	}
+
+	netlify, err := memStor.memFS.ReadFile("_redirects")
+	require.NoError(t, err)
+	require.Equal(t, "/2021/old-post.html /blog/post.html 301!\n/articles/post/ /blog/post.html 301!\n", string(netlify))
+
+	nginx, err := memStor.memFS.ReadFile("redirects.map")
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		"/2021/old-post.html /blog/post.html;\n/articles/post/ /blog/post.html;\n/articles/post/index.html /blog/post.html;\n",
+		string(nginx),
+	)
+}
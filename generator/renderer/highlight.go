@@ -0,0 +1,41 @@
+package renderer
+
+import (
+	"io"
+
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+)
+
+// DefaultHighlightStyle is the name of the style used for syntax highlighting if none is configured.
+const DefaultHighlightStyle = "github"
+
+// HighlightStylesheet is the path of the generated stylesheet for syntax highlighted code blocks.
+const HighlightStylesheet = "static/highlight.css"
+
+// IsHighlightStyle returns true if a syntax highlighting style of the given name exists.
+func IsHighlightStyle(name string) bool {
+	_, ok := styles.Registry[name]
+
+	return ok
+}
+
+// Highlighting returns a goldmark extension for syntax highlighting of fenced code blocks.
+// Tokens are annotated with CSS classes instead of inline styles, the matching stylesheet is written by HighlightCSS.
+// Line numbers and highlighted lines can be set per code block in the info string, e.g. ```go {linenos=true, hl_lines=[2,"4-5"]}.
+func Highlighting(style string, lineNumbers bool) goldmark.Extender {
+	return highlighting.NewHighlighting(
+		highlighting.WithStyle(style),
+		highlighting.WithFormatOptions(
+			html.WithClasses(true),
+			html.WithLineNumbers(lineNumbers),
+		),
+	)
+}
+
+// HighlightCSS writes the stylesheet of the given syntax highlighting style.
+func HighlightCSS(w io.Writer, style string) error {
+	return html.New(html.WithClasses(true)).WriteCSS(w, styles.Get(style))
+}
@@ -0,0 +1,66 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+)
+
+func TestHighlighting(t *testing.T) {
+	tCases := []struct {
+		name        string
+		lineNumbers bool
+		markdown    string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "classes",
+			markdown:    "```go\nfunc main() {}\n```\n",
+			contains:    []string{`<pre class="chroma">`, `<span class="kd">func</span>`},
+			notContains: []string{`style="`, `class="lnt"`},
+		},
+		{
+			name:        "line numbers",
+			lineNumbers: true,
+			markdown:    "```go\nfunc main() {}\n```\n",
+			contains:    []string{`class="ln"`},
+		},
+		{
+			name:     "highlighted lines from info string",
+			markdown: "```go {hl_lines=[2]}\npackage main\nfunc main() {}\n```\n",
+			contains: []string{`<span class="line hl">`},
+		},
+		{
+			name:        "line numbers disabled by info string",
+			lineNumbers: true,
+			markdown:    "```go {linenos=false}\nfunc main() {}\n```\n",
+			notContains: []string{`class="ln"`},
+		},
+	}
+
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			md := goldmark.New(goldmark.WithExtensions(Highlighting(DefaultHighlightStyle, tCase.lineNumbers)))
+			buf := bytes.NewBuffer(nil)
+			require.NoError(t, md.Convert([]byte(tCase.markdown), buf))
+			for _, s := range tCase.contains {
+				require.Contains(t, buf.String(), s)
+			}
+			for _, s := range tCase.notContains {
+				require.NotContains(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+func TestHighlightCSS(t *testing.T) {
+	require.True(t, IsHighlightStyle(DefaultHighlightStyle))
+	require.False(t, IsHighlightStyle("does-not-exist"))
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, HighlightCSS(buf, DefaultHighlightStyle))
+	require.Contains(t, buf.String(), ".chroma .kd")
+}
@@ -0,0 +1,141 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// ErrUnresolvedLink indicates a link to a markdown file that is not part of the content.
+var ErrUnresolvedLink = fmt.Errorf("unresolved link")
+
+// Links resolves links between markdown pages to the URLs of their generated pages.
+type Links struct {
+	baseURL string
+	// paths maps the slash-separated source paths of pages to their output paths.
+	paths map[string]string
+	// unresolved is called with the linking page and an ErrUnresolvedLink error for every link that can not be resolved.
+	// Rendering fails if it returns an error.
+	unresolved func(page string, err error) error
+}
+
+// NewLinks returns a link resolver for the given pages, which maps source paths to output paths.
+// Unresolved links are reported to unresolved, which decides whether rendering fails by returning an error.
+func NewLinks(baseURL string, paths map[string]string, unresolved func(page string, err error) error) *Links {
+	return &Links{baseURL: baseURL, paths: paths, unresolved: unresolved}
+}
+
+// Resolve returns the URL of the page that is referred to by link, including the link's query and fragment.
+// Only relative links and site-absolute links, i.e. starting with a slash, to markdown files are resolved.
+// Other links and links that can not be resolved are returned unchanged.
+func (l *Links) Resolve(page, link string) (string, error) {
+	dest, fragment, hasFragment := strings.Cut(link, "#")
+	dest, query, hasQuery := strings.Cut(dest, "?")
+	if strings.Contains(dest, ":") || strings.HasPrefix(dest, "//") || path.Ext(dest) != ".md" {
+		return link, nil
+	}
+
+	unescaped, err := url.PathUnescape(dest)
+	if err != nil {
+		return link, l.unresolved(page, fmt.Errorf("page %q: %w %q", page, ErrUnresolvedLink, link))
+	}
+	var target string
+	if strings.HasPrefix(unescaped, "/") {
+		target = path.Clean(strings.TrimPrefix(unescaped, "/"))
+	} else {
+		target = path.Join(path.Dir(strings.TrimPrefix(page, "/")), unescaped)
+	}
+
+	out, ok := l.paths[target]
+	if !ok {
+		return link, l.unresolved(page, fmt.Errorf("page %q: %w %q", page, ErrUnresolvedLink, link))
+	}
+
+	resolved := AbsLink(l.baseURL, out)
+	if hasQuery {
+		resolved += "?" + query
+	}
+	if hasFragment {
+		resolved += "#" + fragment
+	}
+
+	return resolved, nil
+}
+
+type linksKey struct{}
+
+// WithLinks returns a context that makes the given links available to the renderer.
+// Links between pages are only rewritten if the context of the rendering call contains links.
+func WithLinks(ctx context.Context, links *Links) context.Context {
+	return context.WithValue(ctx, linksKey{}, links)
+}
+
+// resolvePage returns the page with the output path that is resolved by the links of ctx, if any, see PagePath.
+func resolvePage(ctx context.Context, page TemplatePage) TemplatePage {
+	links, ok := ctx.Value(linksKey{}).(*Links)
+	if !ok {
+		return page
+	}
+	if out, ok := links.paths[filepath.ToSlash(page.Path)]; ok {
+		page.Output = out
+	}
+
+	return page
+}
+
+// linkState is the state of the link rewriter for a single page.
+type linkState struct {
+	links *Links
+	page  string
+	err   error
+}
+
+var linkStateKey = parser.NewContextKey()
+
+// linkRewriter is an AST transformer that rewrites links to markdown files to the URL of their generated page.
+type linkRewriter struct{}
+
+// Transform implements parser.ASTTransformer.
+func (lr linkRewriter) Transform(doc *ast.Document, _ text.Reader, pc parser.Context) {
+	state, ok := pc.Get(linkStateKey).(*linkState)
+	if !ok {
+		return
+	}
+
+	// The walker never fails since errors are recorded in the state.
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		dest, err := state.links.Resolve(state.page, string(link.Destination))
+		if err != nil {
+			state.err = err
+			return ast.WalkStop, nil
+		}
+		link.Destination = []byte(dest)
+
+		return ast.WalkContinue, nil
+	})
+}
+
+// setLinkState prepares the parser context for rendering the given page with the links of ctx, if any.
+func setLinkState(ctx context.Context, pc parser.Context, page TemplatePage) *linkState {
+	links, ok := ctx.Value(linksKey{}).(*Links)
+	if !ok {
+		return nil
+	}
+
+	state := &linkState{links: links, page: filepath.ToSlash(page.Path)}
+	pc.Set(linkStateKey, state)
+
+	return state
+}
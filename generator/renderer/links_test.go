@@ -0,0 +1,60 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinksResolve(t *testing.T) {
+	var unresolved []error
+	links := NewLinks(
+		"https://john.doe",
+		map[string]string{
+			"index.md":               "index.html",
+			"articles/hello.md":      "articles/hello-world.html",
+			"articles/2021/intro.md": "intro/index.html",
+			"notes/my note.md":       "notes/my-note.html",
+		},
+		func(page string, err error) error {
+			unresolved = append(unresolved, err)
+			return nil
+		},
+	)
+
+	tCases := []struct {
+		name       string
+		page       string
+		link       string
+		expected   string
+		unresolved bool
+	}{
+		{"same directory", "articles/bye.md", "hello.md", "https://john.doe/articles/hello-world.html", false},
+		{"parent directory", "notes/todo.md", "../articles/hello.md", "https://john.doe/articles/hello-world.html", false},
+		{"subdirectory", "articles/bye.md", "./2021/intro.md", "https://john.doe/intro/index.html", false},
+		{"site absolute", "notes/todo.md", "/articles/hello.md", "https://john.doe/articles/hello-world.html", false},
+		{"fragment", "articles/bye.md", "hello.md#greeting", "https://john.doe/articles/hello-world.html#greeting", false},
+		{"query", "articles/bye.md", "hello.md?lang=de#greeting", "https://john.doe/articles/hello-world.html?lang=de#greeting", false},
+		{"escaped", "articles/bye.md", "../notes/my%20note.md", "https://john.doe/notes/my-note.html", false},
+		{"leading slash page", "/articles/bye.md", "hello.md", "https://john.doe/articles/hello-world.html", false},
+		{"external", "articles/bye.md", "https://example.com/hello.md", "https://example.com/hello.md", false},
+		{"protocol relative", "articles/bye.md", "//example.com/hello.md", "//example.com/hello.md", false},
+		{"other file", "articles/bye.md", "../images/photo.webp", "../images/photo.webp", false},
+		{"fragment only", "articles/bye.md", "#top", "#top", false},
+		{"missing page", "articles/bye.md", "missing.md#top", "missing.md#top", true},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			unresolved = nil
+			resolved, err := links.Resolve(tCase.page, tCase.link)
+			require.NoError(t, err)
+			require.Equal(t, tCase.expected, resolved)
+			if tCase.unresolved {
+				require.Len(t, unresolved, 1)
+				require.ErrorIs(t, unresolved[0], ErrUnresolvedLink)
+			} else {
+				require.Empty(t, unresolved)
+			}
+		})
+	}
+}
@@ -6,50 +6,120 @@ import (
 	"html/template"
 	"io"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/klingtnet/static-site-generator/generator/model"
-	"github.com/klingtnet/static-site-generator/internal"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
 )
 
 type Renderer interface {
-	Page(context.Context, io.Writer, TemplatePage, []model.MenuEntry) error
+	Page(context.Context, io.Writer, TemplatePage, Navigation, []FeedLink, []model.MenuEntry) error
 	FeedPage(context.Context, io.Writer, TemplatePage) error
-	List(context.Context, io.Writer, model.Tree, []model.MenuEntry) error
+	List(context.Context, io.Writer, model.Tree, Pagination, []FeedLink, []model.MenuEntry) error
+	Tags(context.Context, io.Writer, []*model.Tag, []FeedLink, []model.MenuEntry) error
+	// Summarize derives the excerpt and word count of a page.
+	// Excerpts without summary separator contain the given number of words.
+	Summarize(page TemplatePage, words int) model.Summary
+	// Fingerprint returns a digest of the renderer's configuration, e.g. its templates.
+	// The fingerprint changes whenever the same input would be rendered differently.
+	Fingerprint() string
+	// UsesSite returns true if the rendered output depends on the site data, see WithSite.
+	UsesSite() bool
 }
 
 // Markdown renders markdown pages to HTML websites.
 type Markdown struct {
 	md        goldmark.Markdown
 	templates *Templates
+	// stylesheets are linked by every page in addition to the stylesheets of the base template.
+	stylesheets []string
 }
 
 // NewMarkdown returns an instantiated markdown renderer.
-func NewMarkdown(md goldmark.Markdown, templates *Templates) *Markdown {
+// The given stylesheets, e.g. HighlightStylesheet, are made available to the templates.
+// Links to markdown files are rewritten to their generated pages, see WithLinks,
+// and headings get ids based on their slugified text.
+func NewMarkdown(md goldmark.Markdown, templates *Templates, stylesheets ...string) *Markdown {
+	md.Parser().AddOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(linkRewriter{}, 999)),
+	)
+
 	return &Markdown{
-		md:        md,
-		templates: templates,
+		md:          md,
+		templates:   templates,
+		stylesheets: stylesheets,
+	}
+}
+
+// Fingerprint implements Renderer.
+func (m *Markdown) Fingerprint() string {
+	return m.templates.Digest() + strings.Join(m.stylesheets, ",")
+}
+
+// UsesSite implements Renderer.
+func (m *Markdown) UsesSite() bool {
+	return m.templates.UsesSite()
+}
+
+// convert renders the markdown of the given page to HTML.
+// The table of contents is returned if it is enabled for the page, see TableOfContents.
+func (m *Markdown) convert(ctx context.Context, w io.Writer, page TemplatePage) ([]*TOCEntry, error) {
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs(m.templates.slugifier)))
+	pc.Set(tocPageKey, page.FM.TOC)
+	state := setLinkState(ctx, pc, page)
+	err := m.md.Convert(page.Markdown, w, parser.WithContext(pc))
+	if err != nil {
+		return nil, err
 	}
+	if state != nil && state.err != nil {
+		return nil, state.err
+	}
+	toc, _ := pc.Get(tocKey).([]*TOCEntry)
+
+	return toc, nil
 }
 
 // Page renders a single page.
+// Navigation describes where the page is located within the website.
+// Feeds are links to site-wide feeds, used for discovery by feed readers.
 func (m *Markdown) Page(
 	ctx context.Context,
 	w io.Writer,
 	page TemplatePage,
+	navigation Navigation,
+	feeds []FeedLink,
 	siteMenu []model.MenuEntry,
 ) error {
 	buf := bytes.NewBuffer(nil)
-	err := m.md.Convert(page.Markdown, buf)
+	toc, err := m.convert(ctx, buf, page)
 	if err != nil {
 		return err
 	}
+	page = resolvePage(ctx, page)
+	if navigation.Prev != nil {
+		prev := resolvePage(ctx, *navigation.Prev)
+		navigation.Prev = &prev
+	}
+	if navigation.Next != nil {
+		next := resolvePage(ctx, *navigation.Next)
+		navigation.Next = &next
+	}
 
 	data := TemplateData{
-		page.FM.Title, page.FM.Description,
-		template.HTML(buf.String()),
-		siteMenu,
+		Title:       page.FM.Title,
+		Description: page.Description(),
+		Content:     template.HTML(buf.String()),
+		Menu:        siteMenu,
+		Stylesheets: m.stylesheets,
+		Feeds:       feeds,
+		TOC:         toc,
+		Page:        &page,
+		Navigation:  &navigation,
+		Site:        siteFrom(ctx),
 	}
 
 	return m.templates.Page.ExecuteTemplate(w, "base.gohtml", data)
@@ -58,15 +128,18 @@ func (m *Markdown) Page(
 // FeedPage renders a page for use in a feed.
 func (m *Markdown) FeedPage(ctx context.Context, w io.Writer, page TemplatePage) error {
 	buf := bytes.NewBuffer(nil)
-	err := m.md.Convert(page.Markdown, buf)
+	_, err := m.convert(ctx, buf, page)
 	if err != nil {
 		return err
 	}
+	page = resolvePage(ctx, page)
 
 	data := TemplateData{
-		page.FM.Title, page.FM.Description,
-		template.HTML(buf.String()),
-		nil,
+		Title:       page.FM.Title,
+		Description: page.Description(),
+		Content:     template.HTML(buf.String()),
+		Page:        &page,
+		Site:        siteFrom(ctx),
 	}
 
 	return m.templates.FeedPage.ExecuteTemplate(w, "feed.gohtml", data)
@@ -76,6 +149,11 @@ type TemplatePage struct {
 	Path     string
 	FM       model.FrontMatter
 	Markdown []byte
+	// summary returns the summary of the page, it is nil for pages that are not part of the content.
+	summary func() model.Summary
+	// Output is the output path of the page as resolved by the generator, see PagePath.
+	// It is empty unless the page is rendered with links, see WithLinks.
+	Output string
 }
 
 func NewTemplatePage(page *model.Page) TemplatePage {
@@ -83,16 +161,50 @@ func NewTemplatePage(page *model.Page) TemplatePage {
 		Path:     page.Path(),
 		FM:       *page.Frontmatter(),
 		Markdown: page.Content(),
+		summary:  page.Summary,
 	}
 }
 
-// List renders a list, or directory overview, page.
-func (m *Markdown) List(
-	ctx context.Context,
-	w io.Writer,
-	content model.Tree,
-	siteMenu []model.MenuEntry,
-) error {
+// Excerpt returns the plain text summary of the page, see Markdown.Summarize.
+// The summary is only derived if it is requested, e.g. by a template.
+func (tp TemplatePage) Excerpt() string {
+	if tp.summary == nil {
+		return ""
+	}
+
+	return tp.summary().Excerpt
+}
+
+// WordCount returns the number of words of the page.
+func (tp TemplatePage) WordCount() int {
+	if tp.summary == nil {
+		return 0
+	}
+
+	return tp.summary().WordCount
+}
+
+// ReadingTime returns the estimated reading time in minutes.
+func (tp TemplatePage) ReadingTime() int {
+	if tp.summary == nil {
+		return 0
+	}
+
+	return tp.summary().ReadingTime()
+}
+
+// Description returns the description of the page, or its excerpt if the description is unset.
+func (tp TemplatePage) Description() string {
+	if tp.FM.Description != "" {
+		return tp.FM.Description
+	}
+
+	return tp.Excerpt()
+}
+
+// ListPages returns the visible pages of the given content sorted by creation date, newest first,
+// or in the order configured by the metadata of a directory, see model.DirMeta.
+func ListPages(content model.Tree) []TemplatePage {
 	var pages []TemplatePage
 	for _, child := range content.Children() {
 		page, ok := child.(*model.Page)
@@ -101,26 +213,109 @@ func (m *Markdown) List(
 		}
 	}
 
-	// Sort pages by Date descending
-	sort.SliceStable(pages, func(i, j int) bool {
-		if pages[i].FM.CreatedAt == nil || pages[j].FM.CreatedAt == nil {
-			return false
+	SortPages(pages)
+	if dir, ok := content.(*model.ContentTree); ok && dir.Meta().Sort.By != "" {
+		order := dir.Meta().Sort.Order
+		if order == "" {
+			order = "asc"
+		}
+		// The sort order is validated when the metadata is read.
+		if sorted, err := sortBy(dir.Meta().Sort.By, order, pages); err == nil {
+			pages = sorted
 		}
+	}
 
-		return time.Time(*pages[i].FM.CreatedAt).After(time.Time(*pages[j].FM.CreatedAt))
+	return pages
+}
+
+// SortPages sorts pages by date descending, undated pages come last and pages of the same date are sorted by path.
+func SortPages(pages []TemplatePage) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i].FM.CreatedAt, pages[j].FM.CreatedAt
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case !time.Time(*a).Equal(time.Time(*b)):
+			return time.Time(*a).After(time.Time(*b))
+		default:
+			return pages[i].Path < pages[j].Path
+		}
 	})
+}
+
+// List renders a list, or directory overview, page.
+// Only the pages that belong to the given pagination are rendered.
+// Feeds are links to the feeds of the list, used for discovery by feed readers.
+func (m *Markdown) List(
+	ctx context.Context,
+	w io.Writer,
+	content model.Tree,
+	pagination Pagination,
+	feeds []FeedLink,
+	siteMenu []model.MenuEntry,
+) error {
+	pages := ListPages(content)
+	if pagination.PageSize > 0 {
+		start := (pagination.Page - 1) * pagination.PageSize
+		end := start + pagination.PageSize
+		if start > len(pages) {
+			start = len(pages)
+		}
+		if end > len(pages) {
+			end = len(pages)
+		}
+		pages = pages[start:end]
+	}
+	for idx := range pages {
+		pages[idx] = resolvePage(ctx, pages[idx])
+	}
+
+	description := "List of " + content.Name()
+	if dir, ok := content.(*model.ContentTree); ok && dir.Meta().Description != "" {
+		description = dir.Meta().Description
+	}
 
 	data := TemplateData{
-		internal.TitleCase(content.Name()), "List of " + content.Name(),
-		struct {
+		Title:       model.Title(content),
+		Description: description,
+		Content: struct {
 			Pages []TemplatePage
 			Dir   string
 		}{
 			pages,
 			content.Path(),
 		},
-		siteMenu,
+		Menu:        siteMenu,
+		Pagination:  &pagination,
+		Stylesheets: m.stylesheets,
+		Feeds:       feeds,
+		Site:        siteFrom(ctx),
 	}
 
 	return m.templates.List.ExecuteTemplate(w, "base.gohtml", data)
 }
+
+// Tags renders an overview page of all tags.
+func (m *Markdown) Tags(
+	ctx context.Context,
+	w io.Writer,
+	tags []*model.Tag,
+	feeds []FeedLink,
+	siteMenu []model.MenuEntry,
+) error {
+	data := TemplateData{
+		Title:       "Tags",
+		Description: "List of all tags",
+		Content: struct {
+			Tags []*model.Tag
+		}{
+			tags,
+		},
+		Menu:        siteMenu,
+		Stylesheets: m.stylesheets,
+		Feeds:       feeds,
+		Site:        siteFrom(ctx),
+	}
+
+	return m.templates.Tags.ExecuteTemplate(w, "base.gohtml", data)
+}
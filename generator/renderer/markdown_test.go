@@ -0,0 +1,38 @@
+package renderer
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPages(t *testing.T) {
+	page := func(title, date string) *fstest.MapFile {
+		fm := `{"title":"` + title + `"`
+		if date != "" {
+			fm += `,"created_at":"` + date + `"`
+		}
+		return &fstest.MapFile{Data: []byte("```json\n" + fm + "}\n```\n")}
+	}
+	contentFS := fstest.MapFS{
+		"undated.md": page("Undated", ""),
+		"b.md":       page("B", "2023-01-01"),
+		"a.md":       page("A", "2023-01-01"),
+		"newest.md":  page("Newest", "2023-06-01"),
+		"oldest.md":  page("Oldest", "2021-01-01"),
+		"hidden.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Hidden\",\"hidden\":true}\n```\n"),
+		},
+	}
+	content, err := model.NewContentTree(context.Background(), contentFS, ".")
+	require.NoError(t, err)
+
+	var paths []string
+	for _, page := range ListPages(content) {
+		paths = append(paths, page.Path)
+	}
+	require.Equal(t, []string{"newest.md", "a.md", "b.md", "oldest.md", "undated.md"}, paths)
+}
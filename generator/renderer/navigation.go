@@ -0,0 +1,22 @@
+package renderer
+
+// Navigation describes the position of a page within the website.
+type Navigation struct {
+	// Section is the directory that contains the page, it is unset for pages of the root directory.
+	// The section of an index.md page is the parent of its directory.
+	Section *Breadcrumb
+	// Prev is the page that precedes the page on the list page of its directory, i.e. the next newer one by default,
+	// and Next is the page that follows it.  See ListPages for the order of pages.
+	Prev, Next *TemplatePage
+	// Breadcrumbs lead from the root directory to the section of the page.
+	Breadcrumbs []Breadcrumb
+}
+
+// Breadcrumb is a directory of the website.
+type Breadcrumb struct {
+	// Title of the directory.
+	Title string
+	// Link is the path of the directory's list page relative to the output directory.
+	// It is empty if the directory has no list page.
+	Link string
+}
@@ -0,0 +1,71 @@
+package renderer
+
+import (
+	"path"
+	"strconv"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+)
+
+// Pagination describes a single page of a paginated list.
+type Pagination struct {
+	// Page is the number of the current page, starting with 1.
+	Page int
+	// PageSize is the maximum number of items per page, zero means unlimited.
+	PageSize int
+	// TotalPages is the number of pages of the list.
+	TotalPages int
+	// TotalItems is the number of items of all pages.
+	TotalItems int
+	// Path of the current page's directory.
+	Path string
+	// Prev and Next are the paths of the previous and next page, or empty if there is none.
+	Prev, Next string
+	// Sorted is true if the list is sorted by the metadata of its directory instead of by date, see model.DirMeta.
+	Sorted bool
+}
+
+// paginationPath returns the directory path of the n-th page of a list stored in dir.
+// The first page is stored in dir itself, all following pages are stored in dir/page/<n>.
+func paginationPath(dir string, n int) string {
+	if n == 1 {
+		return dir
+	}
+
+	return path.Join(dir, "page", strconv.Itoa(n))
+}
+
+// Paginate splits the list page of the given content into pages of at most pageSize items.
+// A pageSize of zero disables pagination, i.e. a single page containing all items is returned.
+// Note that there is always at least one page, even if the list is empty.
+func Paginate(content model.Tree, pageSize int) []Pagination {
+	totalItems := len(ListPages(content))
+	totalPages := 1
+	if pageSize > 0 && totalItems > pageSize {
+		totalPages = (totalItems + pageSize - 1) / pageSize
+	}
+
+	dir, ok := content.(*model.ContentTree)
+	sorted := ok && dir.Meta().Sort.By != ""
+
+	paginations := make([]Pagination, 0, totalPages)
+	for n := 1; n <= totalPages; n++ {
+		pagination := Pagination{
+			Page:       n,
+			PageSize:   pageSize,
+			TotalPages: totalPages,
+			TotalItems: totalItems,
+			Path:       paginationPath(content.Path(), n),
+			Sorted:     sorted,
+		}
+		if n > 1 {
+			pagination.Prev = paginationPath(content.Path(), n-1)
+		}
+		if n < totalPages {
+			pagination.Next = paginationPath(content.Path(), n+1)
+		}
+		paginations = append(paginations, pagination)
+	}
+
+	return paginations
+}
@@ -0,0 +1,53 @@
+package renderer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	content, err := model.NewContentTree(context.Background(), testutils.NewTestContentFS(t), ".")
+	require.NoError(t, err)
+
+	var blog model.Tree
+	for _, child := range content.Children() {
+		if child.Path() == "blog" {
+			blog = child
+		}
+	}
+	require.NotNil(t, blog)
+
+	tCases := []struct {
+		name     string
+		pageSize int
+		expected []Pagination
+	}{
+		{
+			"disabled",
+			0,
+			[]Pagination{{Page: 1, TotalPages: 1, TotalItems: 2, Path: "blog"}},
+		},
+		{
+			"single-page",
+			2,
+			[]Pagination{{Page: 1, PageSize: 2, TotalPages: 1, TotalItems: 2, Path: "blog"}},
+		},
+		{
+			"multiple-pages",
+			1,
+			[]Pagination{
+				{Page: 1, PageSize: 1, TotalPages: 2, TotalItems: 2, Path: "blog", Next: "blog/page/2"},
+				{Page: 2, PageSize: 1, TotalPages: 2, TotalItems: 2, Path: "blog/page/2", Prev: "blog"},
+			},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			require.Equal(t, tCase.expected, Paginate(blog, tCase.pageSize))
+		})
+	}
+}
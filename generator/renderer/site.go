@@ -0,0 +1,311 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/slug"
+)
+
+var (
+	// ErrUnknownField indicates a front-matter field that does not exist.
+	ErrUnknownField = fmt.Errorf("unknown front-matter field")
+	// ErrUnknownSortOrder indicates a sort order other than asc and desc.
+	ErrUnknownSortOrder = fmt.Errorf("sort order must be asc or desc")
+)
+
+// Site contains data of the whole website, which is available to all templates as .Site.
+type Site struct {
+	// Author of the website.
+	Author string
+	// BaseURL of the website.
+	BaseURL string
+	// Params are custom parameters of the config.
+	Params map[string]interface{}
+	// Content is the content tree of the website.
+	Content model.Tree
+	// Pages are all visible pages of the website sorted by creation date, newest first.
+	Pages []TemplatePage
+	// Tags are all tags of the website, sorted by path.
+	Tags []*model.Tag
+}
+
+// NewSite returns the site data of the given content.
+// The output paths of pages are determined by pagePath, see TemplatePage.Output.
+func NewSite(
+	author, baseURL string,
+	params map[string]interface{},
+	content model.Tree,
+	tags []*model.Tag,
+	pagePath func(*model.Page) string,
+) *Site {
+	var pages []TemplatePage
+	for _, page := range model.Pages(content) {
+		if !page.Frontmatter().Hidden {
+			tp := NewTemplatePage(page)
+			tp.Output = pagePath(page)
+			pages = append(pages, tp)
+		}
+	}
+	SortPages(pages)
+
+	return &Site{
+		Author:  author,
+		BaseURL: baseURL,
+		Params:  params,
+		Content: content,
+		Pages:   pages,
+		Tags:    tags,
+	}
+}
+
+type siteKey struct{}
+
+// WithSite returns a context that makes the given site data available to the templates.
+func WithSite(ctx context.Context, site *Site) context.Context {
+	return context.WithValue(ctx, siteKey{}, site)
+}
+
+// siteFrom returns the site data of the given context, or nil if there is none.
+func siteFrom(ctx context.Context) *Site {
+	site, _ := ctx.Value(siteKey{}).(*Site)
+
+	return site
+}
+
+// frontMatterFields maps the JSON names of the front-matter fields to their index.
+var frontMatterFields = func() map[string]int {
+	t := reflect.TypeOf(model.FrontMatter{})
+	fields := make(map[string]int, t.NumField())
+	for idx := 0; idx < t.NumField(); idx++ {
+		name, _, _ := strings.Cut(t.Field(idx).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = idx
+		}
+	}
+
+	return fields
+}()
+
+// frontMatterField returns the value of the named front-matter field.
+// Unset optional fields, e.g. dates, are returned as nil.
+func frontMatterField(fm model.FrontMatter, name string) (interface{}, error) {
+	idx, ok := frontMatterFields[name]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", name, ErrUnknownField)
+	}
+
+	value := reflect.ValueOf(fm).Field(idx)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, nil
+		}
+		if date, ok := value.Interface().(*frontmatter.SimpleDate); ok {
+			return time.Time(*date), nil
+		}
+		value = value.Elem()
+	}
+
+	return value.Interface(), nil
+}
+
+// matchesValue returns true if the field value equals the given value.
+// Values are compared by their string representation, list values match if any of their elements matches.
+func matchesValue(field, value interface{}) bool {
+	if field == nil {
+		return false
+	}
+	if t, ok := field.(time.Time); ok {
+		field = t.Format(frontmatter.SimpleDateLayout)
+	}
+	if values, ok := field.([]string); ok {
+		for _, v := range values {
+			if v == fmt.Sprint(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return fmt.Sprint(field) == fmt.Sprint(value)
+}
+
+// lessValue orders field values, dates are compared chronologically and all other values by their string representation.
+func lessValue(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Before(tb)
+		}
+	}
+
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// inSection returns the pages that are part of the given directory or one of its subdirectories.
+func inSection(section string, pages []TemplatePage) []TemplatePage {
+	section = filepath.Clean(strings.TrimPrefix(section, "/"))
+	var selected []TemplatePage
+	for _, page := range pages {
+		dir := filepath.Dir(strings.TrimPrefix(page.Path, "/"))
+		if section == "." || dir == section || strings.HasPrefix(dir, section+"/") {
+			selected = append(selected, page)
+		}
+	}
+
+	return selected
+}
+
+// withTag returns the pages having the given tag.
+// Tags are compared by their slug, as for tag pages.
+func withTag(slugifier *slug.Slugifier, tag string, pages []TemplatePage) []TemplatePage {
+	tag = slugifier.Slugify(tag)
+	var selected []TemplatePage
+	for _, page := range pages {
+		for _, t := range page.FM.Tags {
+			if slugifier.Slugify(t) == tag {
+				selected = append(selected, page)
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+// where returns the pages whose front-matter field matches the given value.
+func where(field string, value interface{}, pages []TemplatePage) ([]TemplatePage, error) {
+	var selected []TemplatePage
+	for _, page := range pages {
+		v, err := frontMatterField(page.FM, field)
+		if err != nil {
+			return nil, err
+		}
+		if matchesValue(v, value) {
+			selected = append(selected, page)
+		}
+	}
+
+	return selected, nil
+}
+
+// sortBy returns the pages sorted by the given front-matter field in ascending (asc) or descending (desc) order.
+// Pages where the field is unset come last.
+func sortBy(field, order string, pages []TemplatePage) ([]TemplatePage, error) {
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("%q: %w", order, ErrUnknownSortOrder)
+	}
+
+	values := make([]interface{}, len(pages))
+	for idx, page := range pages {
+		v, err := frontMatterField(page.FM, field)
+		if err != nil {
+			return nil, err
+		}
+		values[idx] = v
+	}
+
+	// Sort indices instead of pages, such that values and pages remain aligned.
+	indices := make([]int, len(pages))
+	for idx := range indices {
+		indices[idx] = idx
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := values[indices[i]], values[indices[j]]
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case order == "desc":
+			return lessValue(b, a)
+		default:
+			return lessValue(a, b)
+		}
+	})
+
+	sorted := make([]TemplatePage, len(pages))
+	for idx, i := range indices {
+		sorted[idx] = pages[i]
+	}
+
+	return sorted, nil
+}
+
+// limit returns the first n pages.
+func limit(n int, pages []TemplatePage) []TemplatePage {
+	if n >= 0 && n < len(pages) {
+		return pages[:n]
+	}
+
+	return pages
+}
+
+// usesSite returns true if any of the given templates accesses the site data,
+// i.e. a .Site field of the template data or a variable.
+func usesSite(templates ...*template.Template) bool {
+	for _, t := range templates {
+		for _, tmpl := range t.Templates() {
+			if tmpl.Tree != nil && usesField(tmpl.Tree.Root, "Site") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// usesField returns true if the given node, or one of its children, accesses a field with the given name.
+func usesField(node parse.Node, name string) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if usesField(child, name) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return usesField(n.Pipe, name)
+	case *parse.IfNode:
+		return usesField(n.Pipe, name) || usesField(n.List, name) || usesField(n.ElseList, name)
+	case *parse.RangeNode:
+		return usesField(n.Pipe, name) || usesField(n.List, name) || usesField(n.ElseList, name)
+	case *parse.WithNode:
+		return usesField(n.Pipe, name) || usesField(n.List, name) || usesField(n.ElseList, name)
+	case *parse.TemplateNode:
+		return usesField(n.Pipe, name)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if usesField(cmd, name) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if usesField(arg, name) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return usesField(n.Node, name) || (len(n.Field) > 0 && n.Field[0] == name)
+	case *parse.FieldNode:
+		return len(n.Ident) > 0 && n.Ident[0] == name
+	case *parse.VariableNode:
+		return len(n.Ident) > 1 && n.Ident[1] == name
+	}
+
+	return false
+}
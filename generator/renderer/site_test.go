@@ -0,0 +1,160 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/slug"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+)
+
+func TestSiteQueries(t *testing.T) {
+	pages := []TemplatePage{
+		{Path: "blog/new.md", FM: model.FrontMatter{Title: "New", Author: "Jane", CreatedAt: frontmatter.NewSimpleDate(2023, 1, 3), Tags: []string{"Go"}}},
+		{Path: "blog/go/old.md", FM: model.FrontMatter{Title: "Old", Author: "John", CreatedAt: frontmatter.NewSimpleDate(2023, 1, 1), Tags: []string{"go", "testing"}}},
+		{Path: "blogroll/links.md", FM: model.FrontMatter{Title: "Links", Author: "Jane", CreatedAt: frontmatter.NewSimpleDate(2023, 1, 2)}},
+		{Path: "about.md", FM: model.FrontMatter{Title: "About", Author: "John"}},
+	}
+	titles := func(pages []TemplatePage) []string {
+		var titles []string
+		for _, page := range pages {
+			titles = append(titles, page.FM.Title)
+		}
+
+		return titles
+	}
+	slugifier := slug.NewSlugifier('-')
+
+	tCases := []struct {
+		name     string
+		query    func() ([]TemplatePage, error)
+		expected []string
+		err      error
+	}{
+		{
+			name:     "section",
+			query:    func() ([]TemplatePage, error) { return inSection("blog", pages), nil },
+			expected: []string{"New", "Old"},
+		},
+		{
+			name:     "root section",
+			query:    func() ([]TemplatePage, error) { return inSection("/", pages), nil },
+			expected: []string{"New", "Old", "Links", "About"},
+		},
+		{
+			name:     "tag",
+			query:    func() ([]TemplatePage, error) { return withTag(slugifier, "GO", pages), nil },
+			expected: []string{"New", "Old"},
+		},
+		{
+			name:     "where",
+			query:    func() ([]TemplatePage, error) { return where("author", "Jane", pages) },
+			expected: []string{"New", "Links"},
+		},
+		{
+			name:     "where list",
+			query:    func() ([]TemplatePage, error) { return where("tags", "testing", pages) },
+			expected: []string{"Old"},
+		},
+		{
+			name:     "where date",
+			query:    func() ([]TemplatePage, error) { return where("created_at", "2023-01-02", pages) },
+			expected: []string{"Links"},
+		},
+		{
+			name:  "where unknown field",
+			query: func() ([]TemplatePage, error) { return where("weight", 1, pages) },
+			err:   ErrUnknownField,
+		},
+		{
+			name:     "sort by date",
+			query:    func() ([]TemplatePage, error) { return sortBy("created_at", "asc", pages) },
+			expected: []string{"Old", "Links", "New", "About"},
+		},
+		{
+			name:     "sort by title descending",
+			query:    func() ([]TemplatePage, error) { return sortBy("title", "desc", pages) },
+			expected: []string{"Old", "New", "Links", "About"},
+		},
+		{
+			name:  "sort order",
+			query: func() ([]TemplatePage, error) { return sortBy("title", "up", pages) },
+			err:   ErrUnknownSortOrder,
+		},
+		{
+			name:     "limit",
+			query:    func() ([]TemplatePage, error) { return limit(2, pages), nil },
+			expected: []string{"New", "Old"},
+		},
+		{
+			name:     "limit exceeds pages",
+			query:    func() ([]TemplatePage, error) { return limit(10, pages), nil },
+			expected: []string{"New", "Old", "Links", "About"},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			selected, err := tCase.query()
+			require.ErrorIs(t, err, tCase.err)
+			require.Equal(t, tCase.expected, titles(selected))
+		})
+	}
+}
+
+func TestUsesSite(t *testing.T) {
+	tCases := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"none", `{{ .Title }}{{ range .Menu }}{{ .Title }}{{ end }}`, false},
+		{"field", `{{ .Site.Author }}`, true},
+		{"range", `{{ range .Menu }}{{ $.Site.BaseURL }}{{ end }}`, true},
+		{"pipeline", `{{ with .Title }}{{ range limit 3 .Site.Pages }}{{ end }}{{ end }}`, true},
+		{"template", `{{ define "x" }}{{ if .Site }}{{ end }}{{ end }}{{ template "x" . }}`, true},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(template.FuncMap{"limit": limit}).Parse(tCase.text))
+			require.Equal(t, tCase.expected, usesSite(tmpl))
+		})
+	}
+}
+
+func TestPageSite(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"base.gohtml": &fstest.MapFile{
+			Data: []byte(`{{ .Site.Params.tagline }}:{{ range sortBy "title" "asc" .Site.Pages | withTag "go" | limit 1 }} {{ .FM.Title }}{{ end }}`),
+		},
+	}
+	for _, name := range templateFiles[1:] {
+		templateFS[name] = &fstest.MapFile{}
+	}
+	slugifier := slug.NewSlugifier('-')
+	templates, err := NewTemplates("John Doe", "https://john.doe", slugifier, templateFS, nil)
+	require.NoError(t, err)
+	require.True(t, templates.UsesSite())
+
+	content, err := model.NewContentTree(context.Background(), fstest.MapFS{
+		"b.md":      &fstest.MapFile{Data: []byte("```json\n{\"title\":\"B\",\"tags\":[\"go\"]}\n```\n")},
+		"a.md":      &fstest.MapFile{Data: []byte("```json\n{\"title\":\"A\",\"tags\":[\"Go\"]}\n```\n")},
+		"hidden.md": &fstest.MapFile{Data: []byte("```json\n{\"title\":\"0\",\"tags\":[\"go\"],\"hidden\":true}\n```\n")},
+	}, ".")
+	require.NoError(t, err)
+	site := NewSite("John Doe", "https://john.doe", map[string]interface{}{"tagline": "Notes"}, content, nil, func(page *model.Page) string {
+		return PagePath(slugifier, NewTemplatePage(page))
+	})
+	require.Len(t, site.Pages, 2)
+
+	m := NewMarkdown(goldmark.New(), templates)
+	buf := new(bytes.Buffer)
+	err = m.Page(WithSite(context.Background(), site), buf, TemplatePage{Path: "a.md"}, Navigation{}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Notes: A", buf.String())
+}
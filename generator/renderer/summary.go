@@ -0,0 +1,102 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// SummarySeparator separates the excerpt of a page from the rest of its content.
+const SummarySeparator = "<!--more-->"
+
+// DefaultSummaryWords is the number of words of an excerpt if the page has no summary separator.
+const DefaultSummaryWords = 70
+
+// isSummarySeparator returns true if the given node is raw HTML that consists of the SummarySeparator only.
+func isSummarySeparator(n ast.Node, source []byte) bool {
+	var raw []byte
+	switch n := n.(type) {
+	case *ast.HTMLBlock:
+		for idx := 0; idx < n.Lines().Len(); idx++ {
+			line := n.Lines().At(idx)
+			raw = append(raw, line.Value(source)...)
+		}
+	case *ast.RawHTML:
+		for idx := 0; idx < n.Segments.Len(); idx++ {
+			segment := n.Segments.At(idx)
+			raw = append(raw, segment.Value(source)...)
+		}
+	default:
+		return false
+	}
+
+	return string(bytes.TrimSpace(raw)) == SummarySeparator
+}
+
+// plainText returns the text of the given markdown document without markup.
+// Code blocks, raw HTML and permalink anchors are omitted.
+// The length of the text preceding the first SummarySeparator is returned as well, or -1 if there is none.
+func plainText(doc ast.Node, source []byte) (string, int) {
+	var sb strings.Builder
+	separator := -1
+	// The walker never fails since the callback does not return errors.
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := n.(type) {
+		case *ast.HTMLBlock, *ast.RawHTML:
+			if entering && separator < 0 && isSummarySeparator(n, source) {
+				separator = sb.Len()
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Link:
+			if isPermalink(n) {
+				return ast.WalkSkipChildren, nil
+			}
+		case *ast.Text:
+			if entering {
+				sb.Write(n.Segment.Value(source))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					sb.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				sb.Write(n.Value)
+			}
+		}
+		if !entering && n.Type() == ast.TypeBlock {
+			sb.WriteByte(' ')
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return sb.String(), separator
+}
+
+// Summarize implements Renderer.
+// The excerpt is the text up to the SummarySeparator, or the first words of the page if it has no separator.
+// The separator is only recognized as raw HTML, i.e. not inside of code.
+func (m *Markdown) Summarize(page TemplatePage, words int) model.Summary {
+	if words <= 0 {
+		words = DefaultSummaryWords
+	}
+
+	plain, separator := plainText(m.md.Parser().Parse(text.NewReader(page.Markdown)), page.Markdown)
+	fields := strings.Fields(plain)
+	summary := model.Summary{WordCount: len(fields)}
+
+	if separator >= 0 {
+		summary.Excerpt = strings.Join(strings.Fields(plain[:separator]), " ")
+	} else if len(fields) > words {
+		summary.Excerpt = strings.Join(fields[:words], " ") + " …"
+	} else {
+		summary.Excerpt = strings.Join(fields, " ")
+	}
+
+	return summary
+}
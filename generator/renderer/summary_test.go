@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/slug"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+func TestSummarize(t *testing.T) {
+	tCases := []struct {
+		name     string
+		markdown string
+		words    int
+		expected model.Summary
+	}{
+		{
+			name:     "empty",
+			expected: model.Summary{},
+		},
+		{
+			name:     "short",
+			markdown: "# Hello\n\nA *short* [page](other.md).\n",
+			expected: model.Summary{Excerpt: "Hello A short page.", WordCount: 4},
+		},
+		{
+			name:     "first words",
+			markdown: "One two three\nfour five.\n\n```go\nfunc main() {}\n```\n\nSix `seven` <b>eight</b>.\n",
+			words:    3,
+			expected: model.Summary{Excerpt: "One two three …", WordCount: 8},
+		},
+		{
+			name:     "separator",
+			markdown: "The introduction.\n\n<!--more-->\n\nThe rest of the page.\n",
+			words:    1,
+			expected: model.Summary{Excerpt: "The introduction.", WordCount: 7},
+		},
+		{
+			name:     "inline separator",
+			markdown: "The introduction. <!--more--> The rest.\n",
+			expected: model.Summary{Excerpt: "The introduction.", WordCount: 4},
+		},
+		{
+			name:     "separator in code",
+			markdown: "Use `<!--more-->` to end the excerpt.\n\n```html\n<!--more-->\n```\n\nMore text.\n",
+			words:    3,
+			expected: model.Summary{Excerpt: "Use <!--more--> to …", WordCount: 8},
+		},
+		{
+			name:     "permalinks",
+			markdown: "## Heading\n\nText.\n",
+			expected: model.Summary{Excerpt: "Heading Text.", WordCount: 2},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			m := NewMarkdown(
+				goldmark.New(goldmark.WithExtensions(extension.GFM, TableOfContents(TOCOptions{Permalinks: true}))),
+				&Templates{slugifier: slug.NewSlugifier('-')},
+			)
+			summary := m.Summarize(TemplatePage{Markdown: []byte(tCase.markdown)}, tCase.words)
+			require.Equal(t, tCase.expected, summary)
+		})
+	}
+}
+
+func TestSummarizeDefaultWords(t *testing.T) {
+	m := NewMarkdown(goldmark.New(), &Templates{slugifier: slug.NewSlugifier('-')})
+	summary := m.Summarize(TemplatePage{Markdown: []byte(strings.Repeat("word ", 500))}, 0)
+	require.Equal(t, 500, summary.WordCount)
+	require.Equal(t, strings.Repeat("word ", DefaultSummaryWords)+"…", summary.Excerpt)
+	require.Equal(t, 3, summary.ReadingTime())
+}
@@ -1,9 +1,13 @@
 package renderer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"html/template"
 	"io/fs"
 	"path/filepath"
+	"strings"
 
 	"github.com/klingtnet/static-site-generator/generator/model"
 	"github.com/klingtnet/static-site-generator/slug"
@@ -17,34 +21,148 @@ type Templates struct {
 	FeedPage *template.Template
 	// List is a template for list pages, e.g. a list of all blog articles.
 	List *template.Template
+	// Tags is a template for the overview of all tags.
+	Tags *template.Template
+
+	// digest is a hash of all template files.
+	digest string
+	// slugifier is used to generate heading ids.
+	slugifier *slug.Slugifier
+	// usesSite is true if any template accesses the site data.
+	usesSite bool
 }
 
+// templateFiles are the names of all template files that are parsed by NewTemplates.
+var templateFiles = []string{"base.gohtml", "page.gohtml", "feed.gohtml", "list.gohtml", "tags.gohtml"}
+
+// optionalTemplateFiles are template files that are read from the default templates if they are missing,
+// such that template folders created before their introduction remain usable.
+var optionalTemplateFiles = []string{"tags.gohtml"}
+
 // NewTemplates parses templates from the given fs.FS and provides a set of default template functions.
-// The template folder is expected to contain three files, base.gohtml, page.gohtml and list.gohtml, where
-// base.gohtml will be shared by both, the page and list template.
-func NewTemplates(author, baseURL string, slugifier *slug.Slugifier, templateFS fs.FS) *Templates {
+// The template folder is expected to contain the files base.gohtml, page.gohtml, feed.gohtml, list.gohtml
+// and tags.gohtml, where base.gohtml will be shared by the page, list and tags template.
+// A missing tags.gohtml is read from defaultFS instead, if set.
+func NewTemplates(author, baseURL string, slugifier *slug.Slugifier, templateFS, defaultFS fs.FS) (*Templates, error) {
+	if defaultFS != nil {
+		templateFS = &fallbackFS{fsys: templateFS, fallback: defaultFS, names: optionalTemplateFiles}
+	}
 	fns := defaultFuncMap(author, baseURL, slugifier)
+	parse := func(names ...string) (*template.Template, error) {
+		return template.New("").Funcs(fns).ParseFS(templateFS, names...)
+	}
+
+	var err error
+	templates := &Templates{slugifier: slugifier}
+	templates.Page, err = parse("base.gohtml", "page.gohtml")
+	if err != nil {
+		return nil, err
+	}
+	templates.FeedPage, err = parse("feed.gohtml", "page.gohtml")
+	if err != nil {
+		return nil, err
+	}
+	templates.List, err = parse("base.gohtml", "list.gohtml")
+	if err != nil {
+		return nil, err
+	}
+	templates.Tags, err = parse("base.gohtml", "tags.gohtml")
+	if err != nil {
+		return nil, err
+	}
+	templates.digest, err = digestFiles(templateFS, templateFiles)
+	if err != nil {
+		return nil, err
+	}
+	templates.usesSite = usesSite(templates.Page, templates.FeedPage, templates.List, templates.Tags)
 
-	return &Templates{
-		Page: template.Must(
-			template.New("").Funcs(fns).ParseFS(templateFS, "base.gohtml", "page.gohtml"),
-		),
-		FeedPage: template.Must(
-			template.New("").Funcs(fns).ParseFS(templateFS, "feed.gohtml", "page.gohtml"),
-		),
-		List: template.Must(
-			template.New("").Funcs(fns).ParseFS(templateFS, "base.gohtml", "list.gohtml"),
-		),
+	return templates, nil
+}
+
+// fallbackFS opens the given names from fallback if they do not exist in fsys.
+type fallbackFS struct {
+	fsys, fallback fs.FS
+	names          []string
+}
+
+// Open implements fs.FS.
+func (ffs *fallbackFS) Open(name string) (fs.File, error) {
+	f, err := ffs.fsys.Open(name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return f, err
+	}
+	for _, n := range ffs.names {
+		if n == name {
+			return ffs.fallback.Open(name)
+		}
 	}
+
+	return nil, err
 }
 
-// PageLink returns a link for the given page using its slugified title as filename.
+// Digest returns a hash of the template files, which changes whenever one of the templates changes.
+func (t *Templates) Digest() string {
+	return t.digest
+}
+
+// UsesSite returns true if any of the templates accesses the site data, see Site.
+func (t *Templates) UsesSite() bool {
+	return t.usesSite
+}
+
+// digestFiles returns a hex encoded SHA-256 hash over the contents of the given files.
+// Files that do not exist are skipped.
+func digestFiles(fsys fs.FS, names []string) (string, error) {
+	h := sha256.New()
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		// Hash the name as well, to distinguish between content moved from one file to another.
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// URLPath returns the path of the file, relative to the output directory, that is served for the given URL path.
+// URLs with a trailing slash refer to the index.html of the given directory.
+func URLPath(url string) string {
+	url = strings.TrimPrefix(url, "/")
+	if strings.HasSuffix(url, "/") {
+		return filepath.Join(url, "index.html")
+	}
+
+	return filepath.Clean(url)
+}
+
+// PagePath returns the path of the given page relative to the output directory.
+// The url of the front-matter takes precedence, followed by its slug which replaces the filename.
+// Otherwise, pages named index.md are stored as index.html of their directory,
+// all other pages use their slugified title as filename.
+func PagePath(slugifier *slug.Slugifier, page TemplatePage) string {
+	switch {
+	case page.Output != "":
+		return page.Output
+	case page.FM.URL != "":
+		return URLPath(page.FM.URL)
+	case page.FM.Slug != "":
+		return filepath.Join(filepath.Dir(page.Path), page.FM.Slug+".html")
+	case filepath.Base(page.Path) == "index.md":
+		return filepath.Join(filepath.Dir(page.Path), "index.html")
+	default:
+		return filepath.Join(filepath.Dir(page.Path), slugifier.Slugify(page.FM.Title)+".html")
+	}
+}
+
+// PageLink returns an absolute link for the given page, see PagePath.
 func PageLink(baseURL string, slugifier *slug.Slugifier, page TemplatePage) string {
-	return baseURL + filepath.Join(
-		"/",
-		filepath.Dir(page.Path),
-		slugifier.Slugify(page.FM.Title)+".html",
-	)
+	return AbsLink(baseURL, PagePath(slugifier, page))
 }
 
 // AbsLink returns an absolute representation of the given path.
@@ -71,6 +189,13 @@ func defaultFuncMap(author, baseURL string, slugifier *slug.Slugifier) template.
 		},
 		"absLink":          func(path string) string { return AbsLink(baseURL, path) },
 		"replaceExtension": ReplaceExtension,
+		"inSection":        inSection,
+		"withTag": func(tag string, pages []TemplatePage) []TemplatePage {
+			return withTag(slugifier, tag, pages)
+		},
+		"where":  where,
+		"sortBy": sortBy,
+		"limit":  limit,
 	}
 }
 
@@ -79,4 +204,28 @@ type TemplateData struct {
 	Title, Description string
 	Content            interface{}
 	Menu               []model.MenuEntry
+	// Pagination is only set for list pages.
+	Pagination *Pagination
+	// Stylesheets are paths of additional stylesheets, e.g. for syntax highlighting.
+	Stylesheets []string
+	// Feeds are links to feeds related to the page.
+	Feeds []FeedLink
+	// TOC is the table of contents of a page, if enabled.
+	TOC []*TOCEntry
+	// Page is the rendered page, it is unset for list and tag pages.
+	Page *TemplatePage
+	// Navigation is the position of the rendered page, it is unset for list and tag pages.
+	Navigation *Navigation
+	// Site contains data of the whole website, see WithSite.
+	Site *Site
+}
+
+// FeedLink references a feed, e.g. for discovery by feed readers.
+type FeedLink struct {
+	// Title is a human readable title of the feed.
+	Title string
+	// Type is the media type of the feed, e.g. application/rss+xml.
+	Type string
+	// Href is the absolute URL of the feed.
+	Href string
 }
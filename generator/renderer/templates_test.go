@@ -1,7 +1,9 @@
 package renderer
 
 import (
+	"io/fs"
 	"testing"
+	"testing/fstest"
 
 	"github.com/klingtnet/static-site-generator/generator/model"
 	"github.com/klingtnet/static-site-generator/slug"
@@ -81,6 +83,46 @@ func TestPageLink(t *testing.T) {
 			},
 			"https://john.doe/articles/2021/my-first-article.html",
 		},
+		{
+			"index",
+			TemplatePage{
+				Path: "/articles/index.md",
+				FM:   model.FrontMatter{Title: "Articles"},
+			},
+			"https://john.doe/articles/index.html",
+		},
+		{
+			"slug",
+			TemplatePage{
+				Path: "/articles/my-first-article.md",
+				FM:   model.FrontMatter{Title: "My first article", Slug: "first"},
+			},
+			"https://john.doe/articles/first.html",
+		},
+		{
+			"slug-with-dots",
+			TemplatePage{
+				Path: "/articles/go.md",
+				FM:   model.FrontMatter{Title: "Go 1.21", Slug: "go-1.21-released"},
+			},
+			"https://john.doe/articles/go-1.21-released.html",
+		},
+		{
+			"url",
+			TemplatePage{
+				Path: "/articles/my-first-article.md",
+				FM:   model.FrontMatter{Title: "My first article", Slug: "first", URL: "/2021/hello.html"},
+			},
+			"https://john.doe/2021/hello.html",
+		},
+		{
+			"url-directory",
+			TemplatePage{
+				Path: "/articles/my-first-article.md",
+				FM:   model.FrontMatter{Title: "My first article", URL: "hello/"},
+			},
+			"https://john.doe/hello/index.html",
+		},
 	}
 
 	for _, tCase := range tCases {
@@ -93,3 +135,39 @@ func TestPageLink(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTemplates(t *testing.T) {
+	newFS := func(names ...string) fstest.MapFS {
+		fsys := make(fstest.MapFS)
+		for _, name := range names {
+			fsys[name] = &fstest.MapFile{Data: []byte(name)}
+		}
+		return fsys
+	}
+	defaultFS := newFS(templateFiles...)
+
+	tCases := []struct {
+		name       string
+		templateFS fstest.MapFS
+		defaultFS  fs.FS
+		err        bool
+	}{
+		{"complete", newFS(templateFiles...), nil, false},
+		{"tags-fallback", newFS(templateFiles[:4]...), defaultFS, false},
+		{"tags-missing", newFS(templateFiles[:4]...), nil, true},
+		{"page-missing", newFS("base.gohtml", "feed.gohtml", "list.gohtml"), defaultFS, true},
+	}
+
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			templates, err := NewTemplates("John Doe", "https://john.doe", slug.NewSlugifier('-'), tCase.templateFS, tCase.defaultFS)
+			if tCase.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, templates.Tags.Lookup("tags.gohtml"))
+			require.NotEmpty(t, templates.Digest())
+		})
+	}
+}
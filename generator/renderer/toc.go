@@ -0,0 +1,179 @@
+package renderer
+
+import (
+	"strconv"
+
+	"github.com/klingtnet/static-site-generator/slug"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Default heading levels included in a table of contents, see TOCOptions.
+const (
+	DefaultTOCMinLevel = 2
+	DefaultTOCMaxLevel = 3
+)
+
+// TOCEntry is a heading in the table of contents of a page.
+type TOCEntry struct {
+	// Title is the plain text of the heading.
+	Title string
+	// ID is the id of the heading element.
+	ID string
+	// Level of the heading, i.e. 1 for h1.
+	Level int
+	// Children are the subheadings of the heading.
+	Children []*TOCEntry
+}
+
+// TOCOptions configure tables of contents and heading anchors.
+type TOCOptions struct {
+	// MinLevel and MaxLevel limit the heading levels that are part of the table of contents.
+	MinLevel, MaxLevel int
+	// Enabled adds a table of contents to every page, unless its front-matter disables it.
+	// Otherwise, only pages that enable it in their front-matter have a table of contents.
+	Enabled bool
+	// Permalinks appends a permalink anchor to every heading.
+	Permalinks bool
+}
+
+// headingIDs generates slugified and deduplicated ids for heading elements.
+type headingIDs struct {
+	slugifier *slug.Slugifier
+	values    map[string]struct{}
+}
+
+func newHeadingIDs(slugifier *slug.Slugifier) *headingIDs {
+	return &headingIDs{slugifier: slugifier, values: make(map[string]struct{})}
+}
+
+// Generate implements parser.IDs.
+// Duplicate ids get a numeric suffix, e.g. the second "Usage" heading has the id usage-2.
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := ids.slugifier.Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for n := 2; ; n++ {
+		if _, ok := ids.values[id]; !ok {
+			break
+		}
+		id = base + "-" + strconv.Itoa(n)
+	}
+	ids.values[id] = struct{}{}
+
+	return []byte(id)
+}
+
+// Put implements parser.IDs.
+func (ids *headingIDs) Put(value []byte) {
+	ids.values[string(value)] = struct{}{}
+}
+
+// permalinkClass is the class of permalink anchors.
+const permalinkClass = "anchor"
+
+// isPermalink returns true if the link is a permalink anchor of a heading.
+func isPermalink(link *ast.Link) bool {
+	class, ok := link.AttributeString("class")
+
+	return ok && string(class.([]byte)) == permalinkClass
+}
+
+var (
+	// tocPageKey is the value of the front-matter's toc setting of the rendered page.
+	tocPageKey = parser.NewContextKey()
+	// tocKey is the table of contents of the rendered page, if enabled.
+	tocKey = parser.NewContextKey()
+)
+
+// headingTransformer collects the table of contents and adds permalink anchors to headings.
+type headingTransformer struct {
+	options TOCOptions
+}
+
+// Transform implements parser.ASTTransformer.
+func (ht *headingTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	enabled := ht.options.Enabled
+	if pageTOC, ok := pc.Get(tocPageKey).(*bool); ok && pageTOC != nil {
+		enabled = *pageTOC
+	}
+
+	var headings []*ast.Heading
+	// The walker never fails since the callback does not return errors.
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := n.(*ast.Heading); ok && entering {
+			headings = append(headings, heading)
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	var toc []*TOCEntry
+	var parents []*TOCEntry
+	for _, heading := range headings {
+		id, ok := heading.AttributeString("id")
+		if !ok {
+			continue
+		}
+
+		if enabled && heading.Level >= ht.options.MinLevel && heading.Level <= ht.options.MaxLevel {
+			entry := &TOCEntry{Title: string(heading.Text(reader.Source())), ID: string(id.([]byte)), Level: heading.Level}
+			for len(parents) > 0 && parents[len(parents)-1].Level >= entry.Level {
+				parents = parents[:len(parents)-1]
+			}
+			if len(parents) == 0 {
+				toc = append(toc, entry)
+			} else {
+				parent := parents[len(parents)-1]
+				parent.Children = append(parent.Children, entry)
+			}
+			parents = append(parents, entry)
+		}
+
+		if ht.options.Permalinks {
+			anchor := ast.NewLink()
+			anchor.Destination = []byte("#" + string(id.([]byte)))
+			anchor.Title = []byte("Permalink")
+			anchor.SetAttributeString("class", []byte(permalinkClass))
+			anchor.AppendChild(anchor, ast.NewString([]byte("#")))
+			heading.AppendChild(heading, anchor)
+		}
+	}
+
+	if enabled {
+		pc.Set(tocKey, toc)
+	}
+}
+
+type tableOfContents struct {
+	options TOCOptions
+}
+
+// TableOfContents returns a goldmark extension that collects a table of contents of every page, which is made
+// available to the page template, and optionally adds permalink anchors to headings.
+// Unset heading levels default to DefaultTOCMinLevel and DefaultTOCMaxLevel.
+func TableOfContents(options TOCOptions) goldmark.Extender {
+	if options.MinLevel == 0 {
+		options.MinLevel = DefaultTOCMinLevel
+	}
+	if options.MaxLevel == 0 {
+		options.MaxLevel = DefaultTOCMaxLevel
+	}
+
+	return &tableOfContents{options: options}
+}
+
+// Extend implements goldmark.Extender.
+func (t *tableOfContents) Extend(md goldmark.Markdown) {
+	md.Parser().AddOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(&headingTransformer{options: t.options}, 1000)),
+	)
+}
@@ -0,0 +1,85 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/slug"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+)
+
+func TestTableOfContents(t *testing.T) {
+	markdown := []byte("# Title\n\n## Intro\n\n### Details\n\n### Details\n\n## Usage\n\n#### Deep\n\n## Usage\n")
+	enabled, disabled := true, false
+
+	tCases := []struct {
+		name     string
+		options  TOCOptions
+		pageTOC  *bool
+		expected []*TOCEntry
+		contains []string
+	}{
+		{
+			name:    "enabled",
+			options: TOCOptions{Enabled: true},
+			expected: []*TOCEntry{
+				{Title: "Intro", ID: "intro", Level: 2, Children: []*TOCEntry{
+					{Title: "Details", ID: "details", Level: 3},
+					{Title: "Details", ID: "details-2", Level: 3},
+				}},
+				{Title: "Usage", ID: "usage", Level: 2},
+				{Title: "Usage", ID: "usage-2", Level: 2},
+			},
+			contains: []string{`<h1 id="title">Title</h1>`, `<h3 id="details-2">Details</h3>`, `<h4 id="deep">Deep</h4>`},
+		},
+		{
+			name:    "levels",
+			options: TOCOptions{Enabled: true, MinLevel: 1, MaxLevel: 2},
+			expected: []*TOCEntry{
+				{Title: "Title", ID: "title", Level: 1, Children: []*TOCEntry{
+					{Title: "Intro", ID: "intro", Level: 2},
+					{Title: "Usage", ID: "usage", Level: 2},
+					{Title: "Usage", ID: "usage-2", Level: 2},
+				}},
+			},
+		},
+		{
+			name:     "disabled by page",
+			options:  TOCOptions{Enabled: true},
+			pageTOC:  &disabled,
+			contains: []string{`<h2 id="intro">Intro</h2>`},
+		},
+		{
+			name:     "enabled by page",
+			pageTOC:  &enabled,
+			expected: []*TOCEntry{{Title: "Title", ID: "title", Level: 1}},
+			options:  TOCOptions{MinLevel: 1, MaxLevel: 1},
+		},
+		{
+			name:    "permalinks",
+			options: TOCOptions{Permalinks: true},
+			contains: []string{
+				`<h2 id="intro">Intro<a href="#intro" title="Permalink" class="anchor">#</a></h2>`,
+			},
+		},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			m := NewMarkdown(
+				goldmark.New(goldmark.WithExtensions(TableOfContents(tCase.options))),
+				&Templates{slugifier: slug.NewSlugifier('-')},
+			)
+			buf := new(bytes.Buffer)
+			page := TemplatePage{Path: "page.md", FM: model.FrontMatter{TOC: tCase.pageTOC}, Markdown: markdown}
+			toc, err := m.convert(context.Background(), buf, page)
+			require.NoError(t, err)
+			require.Equal(t, tCase.expected, toc)
+			for _, s := range tCase.contains {
+				require.Contains(t, buf.String(), s)
+			}
+		})
+	}
+}
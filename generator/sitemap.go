@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/klingtnet/static-site-generator/frontmatter"
+	"github.com/klingtnet/static-site-generator/generator/model"
+	"github.com/klingtnet/static-site-generator/generator/renderer"
+)
+
+const (
+	// sitemapName is the name of the sitemap, or the sitemap index if the site exceeds sitemapLimit URLs.
+	sitemapName = "sitemap.xml"
+	// sitemapLimit is the maximum number of URLs in a single sitemap as defined by https://www.sitemaps.org/protocol.html.
+	sitemapLimit = 50000
+	// sitemapNamespace is the XML namespace of sitemaps and sitemap indexes.
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	// robotsName is the name of the robots.txt file that references the sitemap.
+	robotsName = "robots.txt"
+)
+
+// sitemapURL is a single entry of a sitemap or sitemap index.
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// lastMod formats the given date for use in a sitemap, a nil date results in an empty string.
+func lastMod(date *frontmatter.SimpleDate) string {
+	if date == nil {
+		return ""
+	}
+
+	return time.Time(*date).Format(frontmatter.SimpleDateLayout)
+}
+
+// newestDate returns the most recent modification date of the given pages, or nil if none of them is dated.
+func newestDate(pages []renderer.TemplatePage) *frontmatter.SimpleDate {
+	var newest *frontmatter.SimpleDate
+	for _, page := range pages {
+		modified := page.FM.ModifiedAt()
+		if modified == nil {
+			continue
+		}
+		if newest == nil || time.Time(*modified).After(time.Time(*newest)) {
+			newest = modified
+		}
+	}
+
+	return newest
+}
+
+// listURLs returns the URLs of all list pages of the given content, see renderer.Paginate.
+// Lists without visible pages are omitted.
+func (g *Generator) listURLs(content model.Tree) []sitemapURL {
+	pages := renderer.ListPages(content)
+	if len(pages) == 0 {
+		return nil
+	}
+	modified := lastMod(newestDate(pages))
+
+	var urls []sitemapURL
+	for _, pagination := range renderer.Paginate(content, g.config.PageSize) {
+		urls = append(urls, sitemapURL{
+			Loc:     renderer.AbsLink(g.config.BaseURL, pagination.Path),
+			LastMod: modified,
+		})
+	}
+
+	return urls
+}
+
+// sitemapURLs returns the sorted URLs of all visible pages, list pages and tag pages.
+func (g *Generator) sitemapURLs(content *model.ContentTree, dirs []*model.ContentTree) ([]sitemapURL, error) {
+	var urls []sitemapURL
+	err := content.Walk(func(tree model.Tree) error {
+		page, ok := tree.(*model.Page)
+		if !ok || page.Frontmatter().Hidden {
+			return nil
+		}
+		urls = append(urls, sitemapURL{
+			Loc:     renderer.AbsLink(g.config.BaseURL, g.pagePath(page)),
+			LastMod: lastMod(page.Frontmatter().ModifiedAt()),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		urls = append(urls, g.listURLs(dir)...)
+	}
+
+	tags := model.Tags(content, tagsDir, g.slugifier.Slugify)
+	if len(tags) > 0 {
+		urls = append(urls, sitemapURL{Loc: renderer.AbsLink(g.config.BaseURL, tagsDir)})
+	}
+	for _, tag := range tags {
+		urls = append(urls, g.listURLs(tag)...)
+	}
+
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].Loc < urls[j].Loc
+	})
+
+	return urls, nil
+}
+
+// splitSitemap splits urls into chunks of at most limit URLs.
+func splitSitemap(urls []sitemapURL, limit int) [][]sitemapURL {
+	var chunks [][]sitemapURL
+	for len(urls) > limit {
+		chunks = append(chunks, urls[:limit])
+		urls = urls[limit:]
+	}
+
+	return append(chunks, urls)
+}
+
+// storeXML stores the XML encoding of v, including the XML header, under the given name.
+func (g *Generator) storeXML(ctx context.Context, name string, v interface{}) error {
+	buf := bytes.NewBufferString(xml.Header)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return g.storeBytes(ctx, name, buf.Bytes())
+}
+
+// renderSitemap renders a sitemap of the whole website.
+// Websites that exceed sitemapLimit URLs get a sitemap index that references multiple sitemaps.
+func (g *Generator) renderSitemap(ctx context.Context, content *model.ContentTree, dirs []*model.ContentTree) error {
+	urls, err := g.sitemapURLs(content, dirs)
+	if err != nil {
+		return err
+	}
+
+	chunks := splitSitemap(urls, sitemapLimit)
+	if len(chunks) == 1 {
+		return g.storeXML(ctx, sitemapName, urlSet{XMLNS: sitemapNamespace, URLs: urls})
+	}
+
+	index := sitemapIndex{XMLNS: sitemapNamespace}
+	for idx, chunk := range chunks {
+		name := fmt.Sprintf("sitemap-%d.xml", idx+1)
+		err = g.storeXML(ctx, name, urlSet{XMLNS: sitemapNamespace, URLs: chunk})
+		if err != nil {
+			return err
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{Loc: renderer.AbsLink(g.config.BaseURL, name)})
+	}
+
+	return g.storeXML(ctx, sitemapName, index)
+}
+
+// renderRobots stores a robots.txt that allows everything and references the sitemap,
+// unless the content or static directory provide their own robots.txt.
+func (g *Generator) renderRobots(ctx context.Context) error {
+	for _, fsys := range []fs.FS{g.sourceFS, g.staticFS} {
+		_, err := fs.Stat(fsys, robotsName)
+		if err == nil {
+			return nil
+		}
+	}
+
+	robots := fmt.Sprintf(
+		"User-agent: *\nAllow: /\n\nSitemap: %s\n",
+		renderer.AbsLink(g.config.BaseURL, sitemapName),
+	)
+
+	return g.storeBytes(ctx, robotsName, []byte(robots))
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/klingtnet/static-site-generator/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorSitemap(t *testing.T) {
+	config := &Config{
+		Author:   "Andreas Linz",
+		BaseURL:  "https://klingt.net",
+		PageSize: 1,
+	}
+	generator, memStor := newTestGenerator(t, config, testutils.NewTestContentFS(t))
+	err := generator.Run(context.Background())
+	require.NoError(t, err)
+
+	data, err := memStor.memFS.ReadFile(sitemapName)
+	require.NoError(t, err)
+	var sitemap urlSet
+	require.NoError(t, xml.Unmarshal(data, &sitemap))
+	require.Equal(t, sitemapNamespace, sitemap.XMLNS)
+	require.Equal(t, []sitemapURL{
+		{Loc: "https://klingt.net/about.html", LastMod: "2021-12-19"},
+		{Loc: "https://klingt.net/blog", LastMod: "2022-01-02"},
+		{Loc: "https://klingt.net/blog/first-article.html", LastMod: "2021-12-19"},
+		{Loc: "https://klingt.net/blog/page/2", LastMod: "2022-01-02"},
+		{Loc: "https://klingt.net/blog/second-article.html", LastMod: "2022-01-02"},
+		{Loc: "https://klingt.net/index.html", LastMod: "2021-12-19"},
+		{Loc: "https://klingt.net/tags"},
+		{Loc: "https://klingt.net/tags/go", LastMod: "2022-01-02"},
+		{Loc: "https://klingt.net/tags/go/page/2", LastMod: "2022-01-02"},
+		{Loc: "https://klingt.net/tags/testing", LastMod: "2021-12-19"},
+	}, sitemap.URLs)
+
+	robots, err := memStor.memFS.ReadFile(robotsName)
+	require.NoError(t, err)
+	require.Contains(t, string(robots), "Sitemap: https://klingt.net/sitemap.xml")
+}
+
+func TestGeneratorSitemapRobots(t *testing.T) {
+	contentFS := fstest.MapFS{
+		"page.md": &fstest.MapFile{
+			Data: []byte("```json\n{\"title\":\"Page\",\"hidden\":true}\n```\n"),
+		},
+		robotsName: &fstest.MapFile{Data: []byte("User-agent: *\nDisallow: /\n")},
+	}
+
+	t.Run("provided robots.txt", func(t *testing.T) {
+		generator, memStor := newTestGenerator(t, &Config{Author: "Andreas Linz", BaseURL: "https://klingt.net"}, contentFS)
+		require.NoError(t, generator.Run(context.Background()))
+
+		robots, err := memStor.memFS.ReadFile(robotsName)
+		require.NoError(t, err)
+		require.Equal(t, "User-agent: *\nDisallow: /\n", string(robots))
+
+		data, err := memStor.memFS.ReadFile(sitemapName)
+		require.NoError(t, err)
+		var sitemap urlSet
+		require.NoError(t, xml.Unmarshal(data, &sitemap))
+		require.Empty(t, sitemap.URLs, "hidden pages must not be listed")
+	})
+
+	t.Run("no base URL", func(t *testing.T) {
+		generator, memStor := newTestGenerator(t, &Config{Author: "Andreas Linz"}, contentFS)
+		require.NoError(t, generator.Run(context.Background()))
+
+		_, err := memStor.memFS.ReadFile(sitemapName)
+		require.Error(t, err)
+	})
+}
+
+func TestSplitSitemap(t *testing.T) {
+	urls := func(n int) []sitemapURL {
+		var urls []sitemapURL
+		for i := 0; i < n; i++ {
+			urls = append(urls, sitemapURL{Loc: fmt.Sprint(i)})
+		}
+		return urls
+	}
+
+	tCases := []struct {
+		name     string
+		urls     int
+		expected []int
+	}{
+		{"empty", 0, []int{0}},
+		{"below limit", 2, []int{2}},
+		{"at limit", 3, []int{3}},
+		{"above limit", 7, []int{3, 3, 1}},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			var sizes []int
+			for _, chunk := range splitSitemap(urls(tCase.urls), 3) {
+				sizes = append(sizes, len(chunk))
+			}
+			require.Equal(t, tCase.expected, sizes)
+		})
+	}
+}
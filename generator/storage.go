@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,13 @@ import (
 // Storage provides methods for persisting files of the generated website.
 type Storage interface {
 	Store(ctx context.Context, name string, content io.Reader) error
+	// Open returns a reader for a previously stored file.
+	// An error wrapping fs.ErrNotExist is returned if there is no such file.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the slash separated names of all stored files.
+	List(ctx context.Context) ([]string, error)
+	// Remove deletes the named file.
+	Remove(ctx context.Context, name string) error
 }
 
 // FileStorage persists to a local file system.
@@ -32,9 +40,7 @@ func (s *FileStorage) Store(ctx context.Context, name string, content io.Reader)
 		return ErrEmptyName
 	}
 
-	// Note that making the name absolute is preventing path traversal.
-	// This is because filepath.Clean is then removing parent directory references, aka double dots.
-	destPath := filepath.Join(s.baseDir, filepath.Clean("/"+name))
+	destPath := s.path(name)
 	err := os.MkdirAll(filepath.Dir(destPath), 0o755)
 	if err != nil {
 		return err
@@ -48,3 +54,96 @@ func (s *FileStorage) Store(ctx context.Context, name string, content io.Reader)
 	_, err = io.Copy(dest, content)
 	return err
 }
+
+// Open implements Storage.
+func (s *FileStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptyName
+	}
+
+	return os.Open(s.path(name))
+}
+
+// List implements Storage.
+func (s *FileStorage) List(ctx context.Context) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(s.baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(s.baseDir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(name))
+
+		return nil
+	})
+
+	return names, err
+}
+
+// Remove implements Storage.
+// Directories that become empty by removing the file are removed as well.
+func (s *FileStorage) Remove(ctx context.Context, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyName
+	}
+
+	path := s.path(name)
+	err := os.Remove(path)
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Clean(s.baseDir)
+	for dir := filepath.Dir(path); dir != baseDir && strings.HasPrefix(dir, baseDir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			// Directory is not empty.
+			break
+		}
+	}
+
+	return nil
+}
+
+// DryRunStorage discards all changes, while reads are passed to the wrapped storage.
+// It determines the outputs of a build without modifying the output directory.
+type DryRunStorage struct {
+	Storage
+}
+
+// NewDryRunStorage returns a DryRunStorage that reads from stor.
+func NewDryRunStorage(stor Storage) *DryRunStorage {
+	return &DryRunStorage{stor}
+}
+
+// Store implements Storage.  The content is consumed but not persisted.
+func (s *DryRunStorage) Store(ctx context.Context, name string, content io.Reader) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyName
+	}
+
+	_, err := io.Copy(io.Discard, content)
+	return err
+}
+
+// Remove implements Storage.  Nothing is removed.
+func (s *DryRunStorage) Remove(ctx context.Context, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyName
+	}
+
+	return nil
+}
+
+// path returns the path of the named file inside the storage's base directory.
+func (s *FileStorage) path(name string) string {
+	// Note that making the name absolute is preventing path traversal.
+	// This is because filepath.Clean is then removing parent directory references, aka double dots.
+	return filepath.Join(s.baseDir, filepath.Clean("/"+name))
+}
@@ -3,6 +3,8 @@ package generator
 import (
 	"bytes"
 	"context"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -35,6 +37,53 @@ func TestStorage(t *testing.T) {
 			content, err := os.ReadFile(filepath.Join(dir, tCase.expectedName))
 			require.NoError(t, err, "could not read destination file")
 			require.Equal(t, string(content), tCase.content)
+
+			f, err := s.Open(context.Background(), tCase.name)
+			require.NoError(t, err)
+			defer f.Close()
+			content, err = io.ReadAll(f)
+			require.NoError(t, err)
+			require.Equal(t, string(content), tCase.content)
 		})
 	}
+
+	_, err := s.Open(context.Background(), "does/not/exist")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestStorageListRemove(t *testing.T) {
+	dir := t.TempDir()
+	s := NewFileStorage(dir)
+	ctx := context.Background()
+
+	for _, name := range []string{"index.html", "blog/index.html", "blog/2021/old.html"} {
+		require.NoError(t, s.Store(ctx, name, bytes.NewBufferString(name)))
+	}
+	names, err := s.List(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"index.html", "blog/index.html", "blog/2021/old.html"}, names)
+
+	require.NoError(t, s.Remove(ctx, "blog/2021/old.html"))
+	names, err = s.List(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"index.html", "blog/index.html"}, names)
+	_, err = os.Stat(filepath.Join(dir, "blog", "2021"))
+	require.ErrorIs(t, err, fs.ErrNotExist, "empty directory was not removed")
+
+	require.ErrorIs(t, s.Remove(ctx, "does/not/exist"), fs.ErrNotExist)
+	require.ErrorIs(t, s.Remove(ctx, ""), ErrEmptyName)
+}
+
+func TestDryRunStorage(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	require.NoError(t, NewFileStorage(dir).Store(ctx, "index.html", bytes.NewBufferString("index")))
+
+	s := NewDryRunStorage(NewFileStorage(dir))
+	require.NoError(t, s.Store(ctx, "about.html", bytes.NewBufferString("about")))
+	require.NoError(t, s.Remove(ctx, "index.html"))
+	names, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"index.html"}, names)
+	require.ErrorIs(t, s.Store(ctx, "", bytes.NewBufferString("")), ErrEmptyName)
 }
@@ -0,0 +1,61 @@
+package fswatcher
+
+import "sort"
+
+type changeKind int
+
+const (
+	added changeKind = iota + 1
+	modified
+	removed
+)
+
+// changeSet accumulates changes of paths, where subsequent changes of the same path are merged.
+type changeSet map[string]changeKind
+
+func (cs changeSet) add(path string) {
+	if cs[path] == removed {
+		// Removed and re-created, e.g. by an editor writing a temporary file and renaming it.
+		cs[path] = modified
+		return
+	}
+	cs[path] = added
+}
+
+func (cs changeSet) modify(path string) {
+	if _, ok := cs[path]; ok {
+		// Added or modified stays as is, removed paths can not be modified.
+		return
+	}
+	cs[path] = modified
+}
+
+func (cs changeSet) remove(path string) {
+	if cs[path] == added {
+		// A path that did not exist before was only temporary.
+		delete(cs, path)
+		return
+	}
+	cs[path] = removed
+}
+
+// result returns a Result containing the sorted paths of every change kind.
+func (cs changeSet) result() Result {
+	var result Result
+	for path, kind := range cs {
+		switch kind {
+		case added:
+			result.Added = append(result.Added, path)
+		case modified:
+			result.Modified = append(result.Modified, path)
+		case removed:
+			result.Removed = append(result.Removed, path)
+		}
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Modified)
+	sort.Strings(result.Removed)
+	result.HasChanged = len(cs) > 0
+
+	return result
+}
@@ -0,0 +1,72 @@
+package fswatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSet(t *testing.T) {
+	tCases := []struct {
+		name     string
+		apply    func(cs changeSet)
+		expected Result
+	}{
+		{
+			name:     "no changes",
+			apply:    func(cs changeSet) {},
+			expected: Result{},
+		},
+		{
+			name: "sorted",
+			apply: func(cs changeSet) {
+				cs.add("b.md")
+				cs.add("a.md")
+				cs.modify("c.md")
+				cs.remove("d.md")
+			},
+			expected: Result{HasChanged: true, Added: []string{"a.md", "b.md"}, Modified: []string{"c.md"}, Removed: []string{"d.md"}},
+		},
+		{
+			name: "modified after add",
+			apply: func(cs changeSet) {
+				cs.add("a.md")
+				cs.modify("a.md")
+			},
+			expected: Result{HasChanged: true, Added: []string{"a.md"}},
+		},
+		{
+			name: "removed and re-created",
+			apply: func(cs changeSet) {
+				cs.remove("a.md")
+				cs.add("a.md")
+			},
+			expected: Result{HasChanged: true, Modified: []string{"a.md"}},
+		},
+		{
+			name: "temporary file",
+			apply: func(cs changeSet) {
+				cs.add("a.md~")
+				cs.modify("a.md~")
+				cs.remove("a.md~")
+			},
+			expected: Result{},
+		},
+		{
+			name: "modified and removed",
+			apply: func(cs changeSet) {
+				cs.modify("a.md")
+				cs.remove("a.md")
+			},
+			expected: Result{HasChanged: true, Removed: []string{"a.md"}},
+		},
+	}
+
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			cs := make(changeSet)
+			tCase.apply(cs)
+			require.Equal(t, tCase.expected, cs.result())
+		})
+	}
+}
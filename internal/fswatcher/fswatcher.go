@@ -1,3 +1,4 @@
+// Package fswatcher reports changes of file systems.
 package fswatcher
 
 import (
@@ -7,21 +8,32 @@ import (
 	"time"
 )
 
+// Watcher reports changes of a file system.
+type Watcher interface {
+	// Watch returns a channel of results.  The watcher stops after a result containing an error was sent.
+	Watch(ctx context.Context) <-chan Result
+}
+
 type fileInfo struct {
 	modTime time.Time
 	size    int64
 	mode    os.FileMode
 }
 
+// FSWatcher watches an fs.FS by periodically comparing the metadata of all files.
 type FSWatcher struct {
 	filesystem fs.FS
 	state      map[string]fileInfo
 	ticker     *time.Ticker
 }
 
+// Result of a single check for changes.
 type Result struct {
 	HasChanged bool
-	Err        error
+	// Added, Modified and Removed contain the sorted, slash separated, paths of changed files
+	// relative to the root of the watched file system.
+	Added, Modified, Removed []string
+	Err                      error
 }
 
 func (fsw *FSWatcher) collectState(state map[string]fileInfo) error {
@@ -50,34 +62,37 @@ func (fsw *FSWatcher) collectState(state map[string]fileInfo) error {
 
 // diff will first walk the watchers filesystem to collect information about all files (ignoring directories)
 // and second it will compare this metadata against the last seen state.
-func (fsw *FSWatcher) diff() (bool, error) {
+func (fsw *FSWatcher) diff() (Result, error) {
 	state := make(map[string]fileInfo, len(fsw.state))
 	err := fsw.collectState(state)
 	if err != nil {
-		return false, err
+		return Result{}, err
 	}
 	defer func() {
 		fsw.state = state
 	}()
 
-	if len(state) != len(fsw.state) {
-		return true, nil
-	}
-
+	changes := make(changeSet)
 	for path, info := range state {
 		lastInfo, ok := fsw.state[path]
 		if !ok {
-			return true, nil
+			changes.add(path)
+			continue
 		}
 
 		if !info.modTime.Equal(lastInfo.modTime) ||
 			info.size != lastInfo.size ||
 			info.mode != lastInfo.mode {
-			return true, nil
+			changes.modify(path)
+		}
+	}
+	for path := range fsw.state {
+		if _, ok := state[path]; !ok {
+			changes.remove(path)
 		}
 	}
 
-	return false, nil
+	return changes.result(), nil
 }
 
 func (fsw *FSWatcher) Watch(ctx context.Context) <-chan Result {
@@ -90,14 +105,14 @@ func (fsw *FSWatcher) Watch(ctx context.Context) <-chan Result {
 
 				return
 			case <-fsw.ticker.C:
-				hasChanged, err := fsw.diff()
+				result, err := fsw.diff()
 				if err != nil {
-					resultCh <- Result{Err: ctx.Err()}
+					resultCh <- Result{Err: err}
 
 					return
 				}
 
-				resultCh <- Result{HasChanged: hasChanged}
+				resultCh <- result
 			}
 		}
 	}(resultCh)
@@ -105,6 +120,7 @@ func (fsw *FSWatcher) Watch(ctx context.Context) <-chan Result {
 	return resultCh
 }
 
+// New returns a polling watcher that checks filesystem for changes on every tick.
 func New(filesystem fs.FS, ticker *time.Ticker) *FSWatcher {
 	return &FSWatcher{
 		filesystem: filesystem,
@@ -112,3 +128,19 @@ func New(filesystem fs.FS, ticker *time.Ticker) *FSWatcher {
 		ticker:     ticker,
 	}
 }
+
+// NewWatcher returns an event based NotifyWatcher if dir is set, i.e. if filesystem was created by os.DirFS(dir).
+// Otherwise, e.g. for embedded file systems, a polling FSWatcher is returned that checks filesystem every interval.
+func NewWatcher(filesystem fs.FS, dir string, interval, debounce time.Duration) Watcher {
+	if dir != "" {
+		return NewNotify(dir, debounce)
+	}
+
+	return New(filesystem, time.NewTicker(interval))
+}
+
+// Ensure that Watcher is implemented.
+var (
+	_ Watcher = &FSWatcher{}
+	_ Watcher = &NotifyWatcher{}
+)
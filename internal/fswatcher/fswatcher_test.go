@@ -31,6 +31,7 @@ func TestFSWatcher(t *testing.T) {
 	result := <-resultCh
 	require.NoError(t, result.Err)
 	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"index.md"}, result.Added)
 
 	// Nothing has changed.
 	result = <-resultCh
@@ -46,6 +47,9 @@ func TestFSWatcher(t *testing.T) {
 	result = <-resultCh
 	require.NoError(t, result.Err)
 	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"new.md"}, result.Added)
+	require.Empty(t, result.Modified)
+	require.Empty(t, result.Removed)
 
 	// Check if a change in modification time is detected.
 	sourceFS.Store("index.md", &fstest.MapFile{
@@ -56,6 +60,7 @@ func TestFSWatcher(t *testing.T) {
 	result = <-resultCh
 	require.NoError(t, result.Err)
 	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"index.md"}, result.Modified)
 
 	// Check if a size change is detected.
 	sourceFS.Store("index.md", &fstest.MapFile{
@@ -75,4 +80,13 @@ func TestFSWatcher(t *testing.T) {
 	result = <-resultCh
 	require.NoError(t, result.Err)
 	require.False(t, result.HasChanged)
+
+	// Check if a removed file is detected.
+	sourceFS.Delete("new.md")
+	result = <-resultCh
+	require.NoError(t, result.Err)
+	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"new.md"}, result.Removed)
+	require.Empty(t, result.Added)
+	require.Empty(t, result.Modified)
 }
@@ -0,0 +1,133 @@
+package fswatcher
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// NotifyWatcher watches a directory of the operating system's file system.
+// Instead of polling, it relies on file system events, i.e. inotify on Linux.
+type NotifyWatcher struct {
+	dir      string
+	debounce time.Duration
+}
+
+// addRecursive adds dir and all of its subdirectories to the watcher.
+// All files that are found are recorded as added.
+func (nw *NotifyWatcher) addRecursive(watcher *fsnotify.Watcher, dir string, changes changeSet) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			changes.add(nw.relative(path))
+			return nil
+		}
+
+		return watcher.Add(path)
+	})
+}
+
+// relative returns the slash separated path relative to the watched directory.
+func (nw *NotifyWatcher) relative(path string) string {
+	rel, err := filepath.Rel(nw.dir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// record adds the given event to the set of changes.
+func (nw *NotifyWatcher) record(watcher *fsnotify.Watcher, event fsnotify.Event, changes changeSet) {
+	path := nw.relative(event.Name)
+	switch {
+	case event.Has(fsnotify.Create):
+		// Stat fails if the path is already gone again, its removal event will cancel out the addition.
+		info, err := os.Stat(event.Name)
+		if err == nil && info.IsDir() {
+			// Newly created directories need to be watched as well.
+			// Errors are ignored since the path might already be gone.
+			_ = nw.addRecursive(watcher, event.Name, changes)
+			return
+		}
+		changes.add(path)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		changes.remove(path)
+	default:
+		changes.modify(path)
+	}
+}
+
+// Watch reports changes of the watched directory.
+// Events are debounced, i.e. a burst of events, like an editor writing a backup, a temporary
+// and the actual file, is reported as a single result after no event occurred for the debounce duration.
+// Similar to FSWatcher the first result reports all existing files as added.
+func (nw *NotifyWatcher) Watch(ctx context.Context) <-chan Result {
+	resultCh := make(chan Result)
+	go func(resultCh chan<- Result) {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			resultCh <- Result{Err: err}
+
+			return
+		}
+		defer watcher.Close()
+
+		changes := make(changeSet)
+		err = nw.addRecursive(watcher, nw.dir, changes)
+		if err != nil {
+			resultCh <- Result{Err: err}
+
+			return
+		}
+
+		// The timer is started immediately to report the initial state.
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				resultCh <- Result{Err: ctx.Err()}
+
+				return
+			case err := <-watcher.Errors:
+				resultCh <- Result{Err: err}
+
+				return
+			case event := <-watcher.Events:
+				nw.record(watcher, event, changes)
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(nw.debounce)
+			case <-timer.C:
+				if len(changes) == 0 {
+					// Changes of the burst cancelled out each other, e.g. a temporary file was created and removed.
+					continue
+				}
+				resultCh <- changes.result()
+				changes = make(changeSet)
+			}
+		}
+	}(resultCh)
+
+	return resultCh
+}
+
+// NewNotify returns a watcher for dir that debounces events for the given duration.
+func NewNotify(dir string, debounce time.Duration) *NotifyWatcher {
+	return &NotifyWatcher{
+		dir:      dir,
+		debounce: debounce,
+	}
+}
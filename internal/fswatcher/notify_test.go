@@ -0,0 +1,74 @@
+package fswatcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyWatcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Hello, World!"), 0o600))
+
+	resultCh := NewNotify(dir, 50*time.Millisecond).Watch(ctx)
+
+	// The first result reports all existing files as added, similar to FSWatcher.
+	result := <-resultCh
+	require.NoError(t, result.Err)
+	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"index.md"}, result.Added)
+
+	// A burst of writes is reported as a single change.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte{byte(i)}, 0o600))
+	}
+	result = <-resultCh
+	require.NoError(t, result.Err)
+	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"index.md"}, result.Modified)
+	select {
+	case result = <-resultCh:
+		require.Fail(t, "burst was not debounced", "unexpected result: %+v", result)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Files in new subdirectories are watched as well.
+	subDir := filepath.Join(dir, "blog")
+	require.NoError(t, os.Mkdir(subDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "first.md"), []byte("first"), 0o600))
+	result = <-resultCh
+	require.NoError(t, result.Err)
+	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"blog/first.md"}, result.Added)
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "first.md"), []byte("changed"), 0o600))
+	result = <-resultCh
+	require.NoError(t, result.Err)
+	require.Equal(t, []string{"blog/first.md"}, result.Modified)
+
+	// Removed files are reported.
+	require.NoError(t, os.Remove(filepath.Join(dir, "index.md")))
+	result = <-resultCh
+	require.NoError(t, result.Err)
+	require.True(t, result.HasChanged)
+	require.Equal(t, []string{"index.md"}, result.Removed)
+
+	// A temporary file that is removed within the debounce window is not reported at all.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md~"), nil, 0o600))
+	require.NoError(t, os.Remove(filepath.Join(dir, "index.md~")))
+	select {
+	case result = <-resultCh:
+		require.Fail(t, "temporary file was reported", "unexpected result: %+v", result)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	result = <-resultCh
+	require.ErrorIs(t, result.Err, context.Canceled)
+}
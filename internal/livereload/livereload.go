@@ -0,0 +1,231 @@
+// Package livereload notifies browsers about rebuilds of the website using server-sent events.
+package livereload
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EventsPath is the URL path of the server-sent events endpoint.
+const EventsPath = "/_livereload/events"
+
+//go:embed livereload.js
+var script string
+
+// event is a server-sent event.
+type event struct {
+	name string
+	data string
+}
+
+// Broker distributes build notifications to all connected browsers.
+type Broker struct {
+	lock    sync.Mutex
+	clients map[chan event]struct{}
+	// lastErr is sent to clients that connect after a failed build.
+	lastErr *event
+}
+
+// NewBroker returns an initialized Broker.
+func NewBroker() *Broker {
+	return &Broker{clients: make(map[chan event]struct{})}
+}
+
+func (b *Broker) broadcast(ev event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for client := range b.clients {
+		select {
+		case client <- ev:
+		default:
+			// Client is not keeping up, it will reconnect anyway.
+		}
+	}
+}
+
+// Reload tells all browsers to reload the current page.
+func (b *Broker) Reload() {
+	b.lock.Lock()
+	b.lastErr = nil
+	b.lock.Unlock()
+
+	b.broadcast(event{name: "reload"})
+}
+
+// Error tells all browsers to show the given build error.
+func (b *Broker) Error(err error) {
+	data, _ := json.Marshal(err.Error())
+	ev := event{name: "build-error", data: string(data)}
+
+	b.lock.Lock()
+	b.lastErr = &ev
+	b.lock.Unlock()
+
+	b.broadcast(ev)
+}
+
+func (b *Broker) subscribe() chan event {
+	client := make(chan event, 1)
+
+	b.lock.Lock()
+	b.clients[client] = struct{}{}
+	if b.lastErr != nil {
+		client <- *b.lastErr
+	}
+	b.lock.Unlock()
+
+	return client
+}
+
+func (b *Broker) unsubscribe(client chan event) {
+	b.lock.Lock()
+	delete(b.clients, client)
+	b.lock.Unlock()
+}
+
+// ServeHTTP implements http.Handler by streaming build notifications as server-sent events.
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	client := b.subscribe()
+	defer b.unsubscribe(client)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-client:
+			data := ev.data
+			if data == "" {
+				data = "{}"
+			}
+			_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.name, data)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// injector buffers HTML responses such that the script can be injected before they are sent.
+// All other responses are passed through unchanged.
+type injector struct {
+	w      http.ResponseWriter
+	status int
+	// decided is true once it is known whether the response is HTML, i.e. when its content type is known.
+	decided bool
+	// buffered is true if the response is HTML and therefore buffered.
+	buffered bool
+	body     bytes.Buffer
+}
+
+func (inj *injector) Header() http.Header {
+	return inj.w.Header()
+}
+
+// decide determines whether the response is buffered, the response is sniffed if the content type is unset.
+func (inj *injector) decide(sniff []byte) {
+	inj.decided = true
+	contentType := inj.w.Header().Get("Content-Type")
+	if contentType == "" && len(sniff) > 0 {
+		contentType = http.DetectContentType(sniff)
+		inj.w.Header().Set("Content-Type", contentType)
+	}
+	inj.buffered = strings.HasPrefix(contentType, "text/html")
+	if !inj.buffered {
+		inj.w.WriteHeader(inj.status)
+	}
+}
+
+func (inj *injector) WriteHeader(status int) {
+	if inj.status != 0 {
+		return
+	}
+	inj.status = status
+	if inj.w.Header().Get("Content-Type") != "" {
+		inj.decide(nil)
+	}
+}
+
+func (inj *injector) Write(b []byte) (int, error) {
+	if inj.status == 0 {
+		inj.status = http.StatusOK
+	}
+	if !inj.decided {
+		inj.decide(b)
+	}
+	if inj.buffered {
+		return inj.body.Write(b)
+	}
+
+	return inj.w.Write(b)
+}
+
+// finish sends the buffered response with the injected script, or the status of an empty response.
+func (inj *injector) finish() {
+	if inj.status == 0 {
+		inj.status = http.StatusOK
+	}
+	if !inj.decided {
+		inj.decide(nil)
+	}
+	if !inj.buffered {
+		return
+	}
+
+	body := InjectScript(inj.body.Bytes())
+	header := inj.w.Header()
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	// The injected script makes the content differ from the file on disk.
+	header.Del("Etag")
+	header.Set("Cache-Control", "no-store")
+	inj.w.WriteHeader(inj.status)
+	_, _ = inj.w.Write(body)
+}
+
+// InjectScript inserts a script tag into the given HTML document that connects to EventsPath.
+// The script is inserted before the closing body tag or appended if there is none.
+func InjectScript(html []byte) []byte {
+	tag := []byte(`<script data-events="` + EventsPath + `">` + script + `</script>`)
+	idx := bytes.LastIndex(bytes.ToLower(html), []byte("</body>"))
+	if idx < 0 {
+		return append(html, tag...)
+	}
+
+	injected := make([]byte, 0, len(html)+len(tag))
+	injected = append(injected, html[:idx]...)
+	injected = append(injected, tag...)
+	return append(injected, html[idx:]...)
+}
+
+// Inject wraps the given handler and injects the livereload script into all HTML responses.
+// Only HTML responses are buffered, other responses as well as HEAD and range requests are passed through unchanged.
+func Inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		inj := &injector{w: w}
+		next.ServeHTTP(inj, r)
+		inj.finish()
+	})
+}
@@ -0,0 +1,21 @@
+(function () {
+  const overlayID = "ssg-livereload-overlay";
+  const source = new EventSource(document.currentScript.dataset.events);
+
+  source.addEventListener("reload", function () {
+    window.location.reload();
+  });
+
+  source.addEventListener("build-error", function (event) {
+    let overlay = document.getElementById(overlayID);
+    if (!overlay) {
+      overlay = document.createElement("pre");
+      overlay.id = overlayID;
+      overlay.style.cssText =
+        "position:fixed;inset:0;margin:0;padding:2em;z-index:2147483647;overflow:auto;" +
+        "white-space:pre-wrap;background:rgba(0,0,0,0.9);color:#ff6b6b;font:14px monospace;";
+      document.body.appendChild(overlay);
+    }
+    overlay.textContent = "Build failed:\n\n" + JSON.parse(event.data);
+  });
+})();
@@ -0,0 +1,124 @@
+package livereload
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectScript(t *testing.T) {
+	injected := string(InjectScript([]byte("<html><body><p>Hello</p></BODY></html>")))
+	require.True(t, strings.HasPrefix(injected, "<html><body><p>Hello</p><script"))
+	require.True(t, strings.HasSuffix(injected, "</script></BODY></html>"))
+
+	injected = string(InjectScript([]byte("<p>Hello</p>")))
+	require.True(t, strings.HasPrefix(injected, "<p>Hello</p><script"))
+}
+
+func TestInject(t *testing.T) {
+	handler := Inject(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/base.css" {
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprint(w, "body {}")
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "<!doctype html><html><body>Not found</body></html>")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/base.css", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "body {}", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing.html", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Contains(t, rec.Body.String(), EventsPath)
+	require.Equal(t, fmt.Sprint(rec.Body.Len()), rec.Header().Get("Content-Length"))
+}
+
+func TestInjectPassThrough(t *testing.T) {
+	page := "<!doctype html><html><body>Hello</body></html>"
+	rec := httptest.NewRecorder()
+	handler := Inject(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/video.mp4" {
+			w.Header().Set("Content-Type", "video/mp4")
+			fmt.Fprint(w, "chunk")
+			// Responses that are not HTML are streamed instead of buffered.
+			require.Equal(t, "chunk", rec.Body.String())
+			return
+		}
+
+		http.ServeContent(w, r, "index.html", time.Time{}, strings.NewReader(page))
+	}))
+
+	tCases := []struct {
+		name     string
+		method   string
+		path     string
+		rangeHdr string
+		status   int
+		expected string
+	}{
+		{"stream", http.MethodGet, "/video.mp4", "", http.StatusOK, "chunk"},
+		{"range", http.MethodGet, "/index.html", "bytes=0-8", http.StatusPartialContent, page[:9]},
+		{"head", http.MethodHead, "/index.html", "", http.StatusOK, ""},
+	}
+	for _, tCase := range tCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			rec = httptest.NewRecorder()
+			req := httptest.NewRequest(tCase.method, tCase.path, nil)
+			if tCase.rangeHdr != "" {
+				req.Header.Set("Range", tCase.rangeHdr)
+			}
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tCase.status, rec.Code)
+			require.Equal(t, tCase.expected, rec.Body.String())
+			require.NotContains(t, rec.Body.String(), EventsPath)
+		})
+	}
+}
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+	server := httptest.NewServer(broker)
+	defer server.Close()
+
+	// A failed build is reported to clients that connect afterwards.
+	broker.Error(fmt.Errorf("bad front-matter"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := events.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+	require.Equal(t, "event: build-error\ndata: \"bad front-matter\"\n", readEvent())
+
+	broker.Reload()
+	require.Equal(t, "event: reload\ndata: {}\n", readEvent())
+}